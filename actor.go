@@ -0,0 +1,62 @@
+package nerr
+
+import "sync"
+
+// Поля пользователя и арендатора, от имени которых выполнялась операция (см. WithActor)
+const (
+	FieldUserID   = "actor.user_id"
+	FieldTenantID = "actor.tenant_id"
+)
+
+var (
+	privateFieldsMu sync.RWMutex
+	privateFields   = map[string]bool{
+		FieldUserID:   true,
+		FieldTenantID: true,
+	}
+)
+
+// WithActor задает идентификаторы пользователя и арендатора в полях FieldUserID и FieldTenantID.
+// Пустые значения не задаются. Поля передаются в журналы и системы отчетов, но не в ответы клиенту (см. PublicFields).
+// Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithActor(err error, userID, tenantID string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+	if len(userID) > 0 {
+		setField(e, FieldUserID, userID)
+	}
+	if len(tenantID) > 0 {
+		setField(e, FieldTenantID, tenantID)
+	}
+	return e
+}
+
+// RegisterPrivateField помечает поле как внутреннее: оно не попадает в PublicFields. Вызывать при инициализации
+func RegisterPrivateField(key string) {
+	privateFieldsMu.Lock()
+	defer privateFieldsMu.Unlock()
+
+	privateFields[key] = true
+}
+
+// IsPrivateField проверяет, что поле помечено как внутреннее
+func IsPrivateField(key string) bool {
+	privateFieldsMu.RLock()
+	defer privateFieldsMu.RUnlock()
+
+	return privateFields[key]
+}
+
+// PublicFields возвращает поля всех уровней цепочки (AllFields), кроме внутренних, для передачи клиенту
+func PublicFields(err error) map[string]any {
+	fields := AllFields(err)
+	for k := range fields {
+		if IsPrivateField(k) {
+			delete(fields, k)
+		}
+	}
+	return fields
+}
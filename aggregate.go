@@ -0,0 +1,132 @@
+package nerr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Summary - сводка по группе одинаковых ошибок (с равным Fingerprint) за интервал
+type Summary struct {
+	Fingerprint string
+	Code        int
+	Op          string
+	Count       int
+	First       time.Time
+	Last        time.Time
+	Sample      *Error
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("code %d (%s) occurred %d times, sample trace: %s",
+		s.Code, s.Op, s.Count, strings.Join(s.Sample.Trace(), " <- "))
+}
+
+// Aggregator накапливает ошибки в течение Window, группирует их по Fingerprint и передает сводки в Emit.
+// Реализует Reporter и предназначен для установки перед журналом во время массовых сбоев.
+// Если задан OnAlert, Aggregator проверяет правила оповещения кодов (см. RegisterAlertPolicy)
+type Aggregator struct {
+	Window time.Duration
+	Emit   func(summaries []Summary)
+	// OnAlert вызывается при превышении порога правила оповещения, не чаще одного раза за интервал правила
+	OnAlert func(alert Alert)
+
+	mu     sync.Mutex
+	groups map[string]*Summary
+	alerts map[int]*alertWindow
+}
+
+// NewAggregator создает агрегатор. Для периодической выдачи сводок нужно запустить Run
+func NewAggregator(window time.Duration, emit func(summaries []Summary)) *Aggregator {
+	return &Aggregator{
+		Window: window,
+		Emit:   emit,
+	}
+}
+
+func (a *Aggregator) Report(_ context.Context, e *Error) {
+	a.Add(e)
+}
+
+// Add учитывает ошибку в текущем интервале
+func (a *Aggregator) Add(e *Error) {
+	if e == nil {
+		return
+	}
+
+	key := Fingerprint(e)
+	code := OutermostCode(e)
+	now := time.Now()
+
+	a.mu.Lock()
+
+	if a.groups == nil {
+		a.groups = make(map[string]*Summary)
+	}
+
+	s, ok := a.groups[key]
+	if !ok {
+		s = &Summary{
+			Fingerprint: key,
+			Code:        code,
+			Op:          InnermostOp(e),
+			First:       now,
+			Sample:      e,
+		}
+		a.groups[key] = s
+	}
+
+	s.Count++
+	s.Last = now
+
+	alert, fire := a.checkAlert(e, code, now)
+	a.mu.Unlock()
+
+	if fire {
+		a.OnAlert(alert)
+	}
+}
+
+// Flush передает в Emit накопленные сводки, отсортированные по убыванию количества, и начинает новый интервал
+func (a *Aggregator) Flush() {
+	a.mu.Lock()
+	groups := a.groups
+	a.groups = nil
+	a.mu.Unlock()
+
+	if len(groups) == 0 {
+		return
+	}
+
+	summaries := make([]Summary, 0, len(groups))
+	for _, s := range groups {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Fingerprint < summaries[j].Fingerprint
+	})
+
+	a.Emit(summaries)
+}
+
+// Run выдает сводки каждые Window до завершения контекста, после чего выдает остаток
+func (a *Aggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.Flush()
+		case <-ctx.Done():
+			a.Flush()
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package nerr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AlertPolicy - правило оповещения для кода ошибки: оповещение выдается, если за Window произошло больше Threshold
+// ошибок с этим кодом (см. RegisterAlertPolicy)
+type AlertPolicy struct {
+	// Name - имя правила, например "page"
+	Name string
+	// Threshold - допустимое количество ошибок за Window
+	Threshold int
+	// Window - интервал подсчета. По умолчанию минута
+	Window time.Duration
+}
+
+// Alert - превышение порога AlertPolicy, обнаруженное Aggregator
+type Alert struct {
+	Code   int
+	Policy AlertPolicy
+	// Count - количество ошибок с кодом в текущем интервале правила на момент превышения
+	Count int
+	// Start - начало интервала правила
+	Start time.Time
+	// Sample - ошибка, на которой порог был превышен
+	Sample *Error
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("alert %s: code %d (%s) occurred %d times in %s, threshold %d",
+		a.Policy.Name, a.Code, CodeName(a.Code), a.Count, a.Policy.window(), a.Policy.Threshold)
+}
+
+func (p AlertPolicy) window() time.Duration {
+	if p.Window > 0 {
+		return p.Window
+	}
+	return time.Minute
+}
+
+var (
+	alertPoliciesMu sync.RWMutex
+	alertPolicies   = map[int]AlertPolicy{}
+)
+
+// RegisterAlertPolicy задает правило оповещения для кода ошибки. Правила проверяет Aggregator с заданным OnAlert.
+// Вызывать при инициализации
+func RegisterAlertPolicy(code int, p AlertPolicy) {
+	alertPoliciesMu.Lock()
+	defer alertPoliciesMu.Unlock()
+
+	alertPolicies[code] = p
+}
+
+// CodeAlertPolicy возвращает правило оповещения для кода ошибки
+func CodeAlertPolicy(code int) (AlertPolicy, bool) {
+	alertPoliciesMu.RLock()
+	defer alertPoliciesMu.RUnlock()
+
+	p, ok := alertPolicies[code]
+	return p, ok
+}
+
+// alertWindow - счетчик ошибок кода в интервале правила
+type alertWindow struct {
+	start time.Time
+	count int
+	fired bool
+}
+
+// checkAlert учитывает ошибку в счетчике правила ее кода и возвращает оповещение при первом превышении порога
+// в интервале. Вызывается под a.mu
+func (a *Aggregator) checkAlert(e *Error, code int, now time.Time) (Alert, bool) {
+	if a.OnAlert == nil {
+		return Alert{}, false
+	}
+	p, ok := CodeAlertPolicy(code)
+	if !ok {
+		return Alert{}, false
+	}
+
+	if a.alerts == nil {
+		a.alerts = make(map[int]*alertWindow)
+	}
+
+	w, ok := a.alerts[code]
+	if !ok || now.Sub(w.start) >= p.window() {
+		w = &alertWindow{start: now}
+		a.alerts[code] = w
+	}
+
+	w.count++
+	if w.fired || w.count <= p.Threshold {
+		return Alert{}, false
+	}
+
+	w.fired = true
+	return Alert{
+		Code:   code,
+		Policy: p,
+		Count:  w.count,
+		Start:  w.start,
+		Sample: e,
+	}, true
+}
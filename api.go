@@ -0,0 +1,100 @@
+package nerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// APIEnvelope - тело ответа с ошибкой в едином для всех сервисов формате. Содержит только сведения,
+// безопасные для клиента. Имена полей JSON не меняются между версиями. Полная цепочка ошибок для обмена между
+// компонентами - Envelope
+type APIEnvelope struct {
+	Code      int         `json:"code"`
+	Message   string      `json:"message"`
+	Details   []APIDetail `json:"details"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// APIDetail - уточнение ошибки, относящееся к полю запроса
+type APIDetail struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewAPIEnvelope формирует тело ответа с ошибкой: код, сообщение для клиента (PublicMessage),
+// ошибки проверки полей или ошибки элементов пакета (PartialResult) и идентификатор запроса.
+// Details всегда не nil, чтобы в JSON был массив
+func NewAPIEnvelope(err error) APIEnvelope {
+	res := APIEnvelope{
+		Code:      OutermostCode(err),
+		Message:   PublicMessage(err),
+		Details:   []APIDetail{},
+		RequestID: RequestID(err),
+	}
+
+	if items := itemDetails(err); len(items) > 0 {
+		res.Details = append(res.Details, items...)
+		return res
+	}
+
+	for _, v := range validationList(err) {
+		res.Details = append(res.Details, APIDetail{Field: v.Field, Rule: v.Rule, Message: v.Message})
+	}
+
+	return res
+}
+
+// ParseAPIEnvelope восстанавливает ошибку из тела ответа в формате APIEnvelope, полученного от другого сервиса,
+// с ограничениями DefaultEnvelopeLimits. Код позволяет проверять ошибку с помощью IsCode, сообщение и идентификатор
+// запроса сохраняются в полях FieldUserMessage и FieldRequestID, details - как ValidationErrors.
+// Полную цепочку ошибок из представления Envelope восстанавливают ParseEnvelope и ErrorFromEnvelope
+func ParseAPIEnvelope(data []byte) (*Error, error) {
+	limits := DefaultEnvelopeLimits
+	if limits.MaxSize > 0 && len(data) > limits.MaxSize {
+		return nil, ErrEnvelopeTooLarge
+	}
+
+	var env *APIEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvelopeInvalid, err)
+	}
+	if env == nil {
+		return nil, fmt.Errorf("%w: null", ErrEnvelopeInvalid)
+	}
+	if limits.MaxItems > 0 && len(env.Details) > limits.MaxItems {
+		return nil, fmt.Errorf("%w: too many items", ErrEnvelopeInvalid)
+	}
+
+	strs := []string{env.Message, env.RequestID}
+	for _, d := range env.Details {
+		strs = append(strs, d.Field, d.Rule, d.Message)
+	}
+	for _, s := range strs {
+		if limits.MaxString > 0 && len(s) > limits.MaxString {
+			return nil, fmt.Errorf("%w: string too long", ErrEnvelopeInvalid)
+		}
+	}
+
+	res := &Error{Code: env.Code}
+
+	if len(env.Details) > 0 {
+		var validation ValidationErrors
+		for _, d := range env.Details {
+			validation.Add(d.Field, d.Rule, d.Message)
+		}
+		res.Err = validation
+	} else if len(env.Message) > 0 {
+		res.Err = errors.New(env.Message)
+	}
+
+	if len(env.Message) > 0 {
+		setField(res, FieldUserMessage, env.Message)
+	}
+	if len(env.RequestID) > 0 {
+		setField(res, FieldRequestID, env.RequestID)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package nerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestParseAPIEnvelope(t *testing.T) {
+	var fields nerr.ValidationErrors
+	fields.Add("email", "email", "invalid email")
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    int
+		wantMessage string
+		wantFields  map[string][]string
+	}{
+		{
+			name:        "not found",
+			err:         nerr.WithRequestID(nerr.New("repo", nerr.ErrNotFound, map[string]any{nerr.FieldUserMessage: "user not found"}), "req-1"),
+			wantCode:    nerr.ErrNotFound,
+			wantMessage: "user not found",
+		},
+		{
+			name:        "validation",
+			err:         nerr.New("handler", map[string]any{nerr.FieldUserMessage: "invalid request"}, fields.Err()),
+			wantCode:    nerr.ErrValidation,
+			wantMessage: "invalid request",
+			wantFields:  map[string][]string{"email": {"invalid email"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(nerr.NewAPIEnvelope(tt.err))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := nerr.ParseAPIEnvelope(data)
+			if err != nil {
+				t.Fatalf("ParseAPIEnvelope(%s) = %v", data, err)
+			}
+
+			if !nerr.IsCode(got, tt.wantCode) {
+				t.Fatalf("code %d not found in %v", tt.wantCode, got)
+			}
+			if msg := nerr.UserMessage(got); msg != tt.wantMessage {
+				t.Fatalf("UserMessage() = %q, want %q", msg, tt.wantMessage)
+			}
+			if id := nerr.RequestID(got); id != nerr.RequestID(tt.err) {
+				t.Fatalf("RequestID() = %q, want %q", id, nerr.RequestID(tt.err))
+			}
+			if tt.wantFields != nil {
+				if f := nerr.ValidationFields(got); len(f) != len(tt.wantFields) || f["email"][0] != "invalid email" {
+					t.Fatalf("ValidationFields() = %v, want %v", f, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAPIEnvelopeInvalid(t *testing.T) {
+	for _, data := range []string{"", "null", "[]", `{"code":"x"}`, `{"code":1} trailing`} {
+		if _, err := nerr.ParseAPIEnvelope([]byte(data)); !errors.Is(err, nerr.ErrEnvelopeInvalid) {
+			t.Fatalf("ParseAPIEnvelope(%q) = %v, want ErrEnvelopeInvalid", data, err)
+		}
+	}
+}
+
+func TestNewAPIEnvelopeJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "code and request id",
+			err:  nerr.WithRequestID(nerr.New("repo", nerr.ErrNotFound, map[string]any{nerr.FieldUserMessage: "user not found"}), "req-1"),
+			want: `{"code":9001,"message":"user not found","details":[],"request_id":"req-1"}`,
+		},
+		{
+			name: "validation",
+			err:  nerr.ValidationErrors{nerr.Validation("email", "email", "invalid email")}.Err(),
+			want: `{"code":9000,"message":"Bad Request","details":[{"field":"email","rule":"email","message":"invalid email"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(nerr.NewAPIEnvelope(tt.err))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("JSON = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package nerr
+
+import (
+	"bytes"
+	"sync"
+)
+
+// буферы для формирования текста ошибок. strings.Builder не подходит: после String() его нельзя переиспользовать
+var bufferPool = sync.Pool{
+	New: func() any {
+		return bytes.NewBuffer(make([]byte, 0, 256))
+	},
+}
+
+// максимальная емкость буфера, возвращаемого в пул
+const maxPooledBuffer = 64 << 10
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
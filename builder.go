@@ -0,0 +1,95 @@
+package nerr
+
+import "context"
+
+// Builder - построитель ошибки, альтернатива New с позиционными аргументами:
+//
+//	nerr.B("store.Save").Code(5001).Err(cause).Field("id", id).Build()
+type Builder struct {
+	ctx    context.Context
+	op     string
+	code   int
+	err    error
+	fields map[string]any
+	skip   int
+
+	noTrace bool
+}
+
+// B начинает построение ошибки операции op
+func B(op string) *Builder {
+	return &Builder{op: op}
+}
+
+// Code задает код ошибки
+func (b *Builder) Code(code int) *Builder {
+	b.code = code
+	return b
+}
+
+// Err задает вложенную ошибку
+func (b *Builder) Err(err error) *Builder {
+	b.err = err
+	return b
+}
+
+// Field добавляет поле ошибки
+func (b *Builder) Field(key string, value any) *Builder {
+	if b.fields == nil {
+		b.fields = make(map[string]any)
+	}
+	b.fields[key] = value
+	return b
+}
+
+// UserMessage задает сообщение для клиента (поле FieldUserMessage)
+func (b *Builder) UserMessage(msg string) *Builder {
+	return b.Field(FieldUserMessage, msg)
+}
+
+// Ctx задает контекст, как в NewCtx: поля, метки, путь операций из PushOp и состояние контекста
+// (FieldContextCanceled, FieldDeadlineRemaining)
+func (b *Builder) Ctx(ctx context.Context) *Builder {
+	b.ctx = ctx
+	return b
+}
+
+// WithoutTrace отключает определение места возникновения, стека и горутины, как в NewLite
+func (b *Builder) WithoutTrace() *Builder {
+	b.noTrace = true
+	return b
+}
+
+// Skip пропускает n дополнительных уровней стека при определении места возникновения, как аргумент Skip в New
+func (b *Builder) Skip(n int) *Builder {
+	b.skip = n
+	return b
+}
+
+// Build создает ошибку. Место возникновения - вызов Build. Как и New, возвращает nil, если не заданы
+// ни операция, ни код, ни причина, ни поля. Ошибка с контекстом (Ctx) создается так же, как NewCtx
+func (b *Builder) Build() error {
+	args := make([]any, 0, 4)
+	args = append(args, b.op, b.code, b.fields)
+	if b.err != nil {
+		args = append(args, b.err)
+	}
+
+	level := 2 + b.skip
+	if b.noTrace {
+		level = noTrace
+	}
+
+	var e *Error
+	if b.ctx != nil {
+		e = newCtxError(level, b.ctx, args)
+	} else {
+		e = newError(level, args)
+	}
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
+}
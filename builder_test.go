@@ -0,0 +1,60 @@
+package nerr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestBuilderNil(t *testing.T) {
+	var err error = nerr.B("").Build()
+	if err != nil {
+		t.Fatalf("Build() = %#v, want untyped nil", err)
+	}
+}
+
+func TestBuilderCtxLikeNewCtx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(nerr.PushOp(context.Background(), "handler"), time.Minute)
+	cancel()
+
+	boom := errors.New("boom")
+	built := nerr.B("repo").Ctx(ctx).Code(nerr.ErrNotFound).Field("id", 1).Err(boom).Build()
+	created := nerr.NewCtx(ctx, "repo", nerr.ErrNotFound, map[string]any{"id": 1}, boom)
+
+	for _, err := range []error{built, created} {
+		e, ok := err.(*nerr.Error)
+		if !ok {
+			t.Fatalf("want *nerr.Error, got %#v", err)
+		}
+		if e.Op != "handler/repo" {
+			t.Fatalf("Op = %q, want handler/repo", e.Op)
+		}
+		if v, _ := nerr.Field(e, nerr.FieldContextCanceled); v != true {
+			t.Fatalf("%s = %v, want true", nerr.FieldContextCanceled, v)
+		}
+		if _, ok := nerr.Field(e, nerr.FieldDeadlineRemaining); !ok {
+			t.Fatalf("%s not set", nerr.FieldDeadlineRemaining)
+		}
+		if !strings.Contains(e.Place, "builder_test.go") {
+			t.Fatalf("Place = %q, want builder_test.go", e.Place)
+		}
+		if !errors.Is(e, boom) || !nerr.IsCode(e, nerr.ErrNotFound) {
+			t.Fatalf("cause or code lost: %v", e)
+		}
+	}
+
+	// значения FieldDeadlineRemaining различаются, сравниваются только ключи
+	builtFields, createdFields := nerr.AllFields(built), nerr.AllFields(created)
+	if len(builtFields) != len(createdFields) {
+		t.Fatalf("Build() fields %v, NewCtx() fields %v", builtFields, createdFields)
+	}
+	for k := range createdFields {
+		if _, ok := builtFields[k]; !ok {
+			t.Fatalf("Build() has no field %s", k)
+		}
+	}
+}
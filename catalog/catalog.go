@@ -0,0 +1,98 @@
+// Package catalog - каталог кодов ошибок, зарегистрированных через nerr.Define, для документации API.
+// Генерируется командой github.com/n-r-w/nerr/cmd/nerrcatalog
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Форматы каталога
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Entry - описание кода ошибки
+type Entry struct {
+	Code       int    `json:"code"`
+	Name       string `json:"name"`
+	HTTPStatus int    `json:"http_status"`
+	Message    string `json:"message,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+	DocURL     string `json:"doc_url,omitempty"`
+	Place      string `json:"place,omitempty"`
+}
+
+// Build собирает каталог зарегистрированных кодов, упорядоченный по значению кода
+func Build() []Entry {
+	type key struct {
+		code int
+		name string
+	}
+	seen := make(map[key]bool)
+
+	var res []Entry
+	for _, info := range nerr.Codes() {
+		k := key{code: info.Code, name: info.Name}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		msg := nerr.LookupCodeMessage(info.Code)
+		res = append(res, Entry{
+			Code:       info.Code,
+			Name:       info.Name,
+			HTTPStatus: nerr.CodeHTTPStatus(info.Code),
+			Message:    msg.Message,
+			Hint:       msg.Hint,
+			DocURL:     msg.DocURL,
+			Place:      info.Place,
+		})
+	}
+
+	return res
+}
+
+// Write записывает каталог в формате format
+func Write(w io.Writer, format string, entries []Entry) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, entries)
+	case FormatMarkdown:
+		return WriteMarkdown(w, entries)
+	default:
+		return fmt.Errorf("catalog: unknown format %q", format)
+	}
+}
+
+// WriteJSON записывает каталог в виде JSON массива
+func WriteJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteMarkdown записывает каталог в виде таблицы Markdown
+func WriteMarkdown(w io.Writer, entries []Entry) error {
+	var b strings.Builder
+
+	b.WriteString("| Code | Name | HTTP status | Message | Defined in |\n")
+	b.WriteString("|------|------|-------------|---------|------------|\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "| %d | %s | %d | %s | %s |\n",
+			e.Code, escapeMarkdown(e.Name), e.HTTPStatus, escapeMarkdown(e.Message), escapeMarkdown(e.Place))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,80 @@
+package nerr
+
+// Clone возвращает копию уровня ошибки. Fields, Labels и Stack копируются, вложенная ошибка остается общей
+func (e *Error) Clone() *Error {
+	if e == nil {
+		return nil
+	}
+
+	// поля копируются по одному: кэш текста может одновременно записываться в Error()
+	res := &Error{
+		Op:        e.Op,
+		Code:      e.Code,
+		Place:     e.Place,
+		Err:       e.Err,
+		Stack:     e.Stack,
+		Goroutine: e.Goroutine,
+		Labels:    e.Labels,
+		Time:      e.Time,
+		Fields:    e.Fields,
+		lazyOp:    e.lazyOp,
+	}
+
+	if e.Fields != nil {
+		res.Fields = make(map[string]any, len(e.Fields))
+		for k, v := range e.Fields {
+			res.Fields[k] = v
+		}
+	}
+
+	if e.Labels != nil {
+		res.Labels = make(map[string]string, len(e.Labels))
+		for k, v := range e.Labels {
+			res.Labels[k] = v
+		}
+	}
+
+	if e.values != nil {
+		res.values = make([]any, len(e.values))
+		copy(res.values, e.values)
+	}
+
+	if e.Stack != nil {
+		res.Stack = make([]Frame, len(e.Stack))
+		copy(res.Stack, e.Stack)
+	}
+
+	return res
+}
+
+// WithCode возвращает копию ошибки с кодом code. Исходная ошибка не изменяется,
+// поэтому метод безопасен для ошибок, объявленных на уровне пакета
+func (e *Error) WithCode(code int) *Error {
+	res := e.Clone()
+	res.Code = code
+	return res
+}
+
+// WithFields возвращает копию ошибки с добавленными полями. Исходная ошибка не изменяется
+func (e *Error) WithFields(fields map[string]any) *Error {
+	res := e.Clone()
+	for k, v := range fields {
+		setField(res, k, v)
+	}
+	return res
+}
+
+// AddOp возвращает копию ошибки с операцией op, добавленной к Op так же, как в New. Исходная ошибка не изменяется
+func (e *Error) AddOp(op string) *Error {
+	res := e.Clone()
+	prepareProperty(res, op)
+	return res
+}
+
+// cloneOrWrap возвращает копию *Error или уровень без места возникновения, оборачивающий стороннюю ошибку
+func cloneOrWrap(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e.Clone()
+	}
+	return &Error{Err: err}
+}
@@ -0,0 +1,115 @@
+// Команда nerrcatalog формирует каталог кодов ошибок, зарегистрированных через nerr.Define в указанных пакетах.
+//
+// Использование в go:generate:
+//
+//	//go:generate go run github.com/n-r-w/nerr/cmd/nerrcatalog -format markdown -o ERRORS.md ./...
+//
+// Команда собирает временную программу, импортирующую пакеты, и выводит каталог, построенный при ее запуске
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var program = template.Must(template.New("main").Parse(`// Code generated by nerrcatalog. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/n-r-w/nerr/catalog"
+{{range .Packages}}
+	_ "{{.}}"
+{{- end}}
+)
+
+func main() {
+	if err := catalog.Write(os.Stdout, {{printf "%q" .Format}}, catalog.Build()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+func main() {
+	format := flag.String("format", "json", "catalog format: json or markdown")
+	output := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	if err := run(*format, *output, patterns); err != nil {
+		fmt.Fprintln(os.Stderr, "nerrcatalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format, output string, patterns []string) error {
+	packages, err := listPackages(patterns)
+	if err != nil {
+		return err
+	}
+
+	// временный каталог внутри текущего модуля, чтобы импорты разрешались его go.mod
+	dir, err := os.MkdirTemp(".", ".nerrcatalog")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	if err := program.Execute(&src, struct {
+		Packages []string
+		Format   string
+	}{packages, format}); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0o600); err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("go", "run", "./"+filepath.ToSlash(dir))
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	if len(output) == 0 {
+		_, err = os.Stdout.Write(stdout.Bytes())
+		return err
+	}
+	return os.WriteFile(output, stdout.Bytes(), 0o644)
+}
+
+// listPackages возвращает пути импорта пакетов, кроме main, которые нельзя импортировать
+func listPackages(patterns []string) ([]string, error) {
+	args := append([]string{"list", "-f", "{{if ne .Name \"main\"}}{{.ImportPath}}{{end}}"}, patterns...)
+
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+
+	var res []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			res = append(res, line)
+		}
+	}
+	return res, nil
+}
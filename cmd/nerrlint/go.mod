@@ -0,0 +1,15 @@
+module github.com/n-r-w/nerr/cmd/nerrlint
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr/nerrlint v0.0.0-20261016022808-a66b0c6d80ab
+	golang.org/x/tools v0.5.0
+)
+
+require (
+	golang.org/x/mod v0.7.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
+)
+
+replace github.com/n-r-w/nerr/nerrlint => ../../nerrlint
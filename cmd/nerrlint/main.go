@@ -0,0 +1,12 @@
+// Команда nerrlint - запуск анализатора nerrlint отдельно или через go vet -vettool
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/n-r-w/nerr/nerrlint"
+)
+
+func main() {
+	singlechecker.Main(nerrlint.Analyzer)
+}
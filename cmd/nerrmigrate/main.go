@@ -0,0 +1,14 @@
+// Команда nerrmigrate - замена устаревших вызовов nerr (см. nerrlint.MigrateAnalyzer):
+//
+//	nerrmigrate -fix ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/n-r-w/nerr/nerrlint"
+)
+
+func main() {
+	singlechecker.Main(nerrlint.MigrateAnalyzer)
+}
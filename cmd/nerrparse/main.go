@@ -0,0 +1,81 @@
+// Команда nerrparse восстанавливает структуру ошибок nerr из текста журналов и выводит ее в JSON.
+//
+// Использование:
+//
+//	nerrparse [-trace] [файл...]
+//
+// По умолчанию каждая строка ввода разбирается как текст Error() и выводится отдельным объектом JSON.
+// С флагом -trace строки разбираются как вывод Trace() и выводятся одним массивом
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/n-r-w/nerr/nerrparse"
+)
+
+func main() {
+	trace := flag.Bool("trace", false, "parse lines as Trace() output")
+	flag.Parse()
+
+	if err := run(flag.Args(), *trace, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string, trace bool, w io.Writer) error {
+	var lines []string
+	if len(files) == 0 {
+		var err error
+		if lines, err = readLines(os.Stdin); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		fileLines, err := readLines(f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fileLines...)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if trace {
+		enc.SetIndent("", "  ")
+		return enc.Encode(nerrparse.ParseTrace(lines))
+	}
+
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+		if err := enc.Encode(nerrparse.ParseError(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines, sc.Err()
+}
@@ -0,0 +1,24 @@
+package nerr
+
+// Встроенные коды ошибок. Диапазон 9000-9099 зарезервирован пакетом, их имена доступны через CodeName
+const (
+	ErrValidation = 9000 + iota
+	ErrNotFound
+	ErrTimeout
+	ErrCanceled
+	ErrUnavailable
+	ErrConflict
+	ErrRateLimited
+	ErrPermissionDenied
+	ErrEOF
+	ErrUnexpectedEOF
+	ErrCertificateInvalid
+	ErrCertificateHostname
+	ErrTLSHandshakeTimeout
+	ErrTxBegin
+	ErrTxCommit
+	ErrTxRollback
+	ErrPanic
+	ErrNotImplemented
+	ErrUnsupported
+)
@@ -0,0 +1,80 @@
+package nerr
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode - режим цветного вывода FprintPretty
+type ColorMode int
+
+const (
+	// ColorNever - без цвета (по умолчанию)
+	ColorNever ColorMode = iota
+	// ColorAuto - цвет, если вывод идет в терминал и не задана переменная окружения NO_COLOR
+	ColorAuto
+	// ColorAlways - всегда цвет
+	ColorAlways
+)
+
+// palette - escape-последовательности ANSI для элементов Pretty. Пустая палитра - вывод без цвета
+type palette struct {
+	op    string
+	code  string
+	place string
+	cause string
+	reset string
+}
+
+var ansiPalette = palette{
+	op:    "\x1b[1;36m", // жирный голубой
+	code:  "\x1b[1;33m", // жирный желтый
+	place: "\x1b[2m",    // приглушенный
+	cause: "\x1b[31m",   // красный
+	reset: "\x1b[0m",
+}
+
+// FprintPretty записывает в w представление Pretty, раскрашенное согласно Config.Color
+func FprintPretty(w io.Writer, err error) error {
+	if err == nil {
+		_, werr := io.WriteString(w, "<nil>\n")
+		return werr
+	}
+
+	p := palette{}
+	if useColor(w, cfg().Color) {
+		p = ansiPalette
+	}
+
+	var b strings.Builder
+	writePretty(&b, err, "", p)
+	writeDocURL(&b, err, p)
+
+	_, werr := io.WriteString(w, b.String())
+	return werr
+}
+
+func useColor(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorAuto:
+		if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+			return false
+		}
+		return isTerminal(w)
+	default:
+		return false
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,72 @@
+package nerr
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Compact возвращает краткий текст ошибки для пользовательских журналов: внешнюю операцию и код,
+// например "payments.Charge [5002]". Для сторонних ошибок - их текст. Полная цепочка выводится через %+v
+func Compact(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var op string
+	for cur := err; cur != nil; {
+		e, ok := cur.(*Error)
+		if !ok {
+			break
+		}
+		if op = e.Operation(); len(op) > 0 {
+			op = SanitizeString(op)
+			break
+		}
+		cur = e.Err
+	}
+
+	code := OutermostCode(err)
+	switch {
+	case len(op) > 0 && code != 0:
+		return op + " [" + strconv.Itoa(code) + "]"
+	case len(op) > 0:
+		return op
+	case code != 0:
+		return "[" + strconv.Itoa(code) + "]"
+	}
+
+	if _, ok := err.(*Error); !ok {
+		return messageText(err.Error())
+	}
+	return causeText(rootCause(err))
+}
+
+// rootCause возвращает первую ошибку цепочки, не являющуюся *Error
+func rootCause(err error) error {
+	for {
+		e, ok := err.(*Error)
+		if !ok || e.Err == nil {
+			return err
+		}
+		err = e.Err
+	}
+}
+
+// Format реализует fmt.Formatter: %s и %v выводят Error(), %+v - дерево Pretty, %q - Error() в кавычках
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, Pretty(e))
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = io.WriteString(s, strconv.Quote(e.Error()))
+	default:
+		fmt.Fprintf(s, "%%!%c(*nerr.Error=%s)", verb, e.Error())
+	}
+}
@@ -0,0 +1,232 @@
+package nerr
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Config - глобальные настройки пакета. Хранится атомарно, поэтому может изменяться во время работы без гонок
+type Config struct {
+	// StackDepth - количество кадров стека, сохраняемых в Error.Stack. При <= 0 запоминается только Place
+	StackDepth int
+	// StackSampler выбирает ошибки, для которых сохраняется полный стек. nil - для всех
+	StackSampler StackSampler
+	// SkipPackages - префиксы полных имен функций, кадры которых исключаются из Place и Stack
+	SkipPackages []string
+	// CaptureTime - запись времени создания ошибки в Error.Time
+	CaptureTime bool
+	// CaptureGoroutine - запись идентификатора горутины и меток pprof
+	CaptureGoroutine bool
+	// CaptureEnvironment - добавление в Fields самого вложенного уровня nerr имени хоста, PID и ревизии VCS
+	CaptureEnvironment bool
+	// TrimSourcePaths - приведение путей к исходникам к виду, который дает сборка с -trimpath
+	TrimSourcePaths bool
+	// Formatter заменяет стандартный текст Error(). Стандартный текст возвращает FormatDefault
+	Formatter func(e *Error) string
+	// Hooks - функции, вызываемые для каждой созданной ошибки
+	Hooks []func(e *Error)
+	// Redact маскирует значения полей: вместо value в ошибке сохраняется результат
+	Redact func(key string, value any) any
+	// MaxMessageLength - максимальная длина текста сторонней ошибки в байтах при выводе. 0 - без ограничения
+	MaxMessageLength int
+	// Color - режим цветного вывода FprintPretty
+	Color ColorMode
+	// ForeignCodes - учитывать в OutermostCode, IsCode и HasAnyCode коды сторонних ошибок с методом Code() int
+	ForeignCodes bool
+	// MaxChainDepth - максимальное количество уровней цепочки в Error(), Trace и Pretty.
+	// Остальные уровни заменяются отметкой "… N more". 0 - без ограничения
+	MaxChainDepth int
+	// CrossGoroutineStacks - запись идентификатора горутины и полного стека места оборачивания, если ошибка
+	// создана в другой горутине. Pretty выводит стеки создания и оборачивания отдельными разделами
+	CrossGoroutineStacks bool
+	// Enrichers - функции, дополняющие ошибки, созданные с контекстом (NewCtx, Builder.Ctx), например полями
+	// региона, имени и версии сервиса. Вызываются после заполнения ошибки, перед хуками
+	Enrichers []func(ctx context.Context, e *Error)
+	// Sanitize - очистка операций, текстов сторонних ошибок и значений полей при выводе
+	Sanitize Sanitize
+	// Service - имя сервиса. Записывается в уровни сериализованного представления (Envelope, nerrpb), чтобы
+	// получатель мог отличить уровни, созданные в другом сервисе (см. Error.Remote)
+	Service string
+}
+
+// Option изменяет Config (см. Configure)
+type Option func(c *Config)
+
+var (
+	configMu      sync.Mutex
+	currentConfig atomic.Pointer[Config]
+	defaultConfig Config
+)
+
+func cfg() *Config {
+	if c := currentConfig.Load(); c != nil {
+		return c
+	}
+	return &defaultConfig
+}
+
+// Configure применяет изменения к копии текущих настроек и атомарно заменяет их
+func Configure(opts ...Option) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	c := cfg().clone()
+	for _, opt := range opts {
+		opt(c)
+	}
+	currentConfig.Store(c)
+}
+
+// CurrentConfig возвращает копию текущих настроек
+func CurrentConfig() Config {
+	return *cfg().clone()
+}
+
+func (c *Config) clone() *Config {
+	res := *c
+	res.SkipPackages = append([]string(nil), c.SkipPackages...)
+	res.Hooks = append(([]func(e *Error))(nil), c.Hooks...)
+	res.Enrichers = append(([]func(ctx context.Context, e *Error))(nil), c.Enrichers...)
+	return &res
+}
+
+// WithConfig заменяет все настройки на c
+func WithConfig(c Config) Option {
+	return func(dst *Config) {
+		*dst = *c.clone()
+	}
+}
+
+// WithStackDepth задает Config.StackDepth
+func WithStackDepth(depth int) Option {
+	return func(c *Config) {
+		c.StackDepth = depth
+	}
+}
+
+// WithSkipPackages добавляет префиксы в Config.SkipPackages
+func WithSkipPackages(prefixes ...string) Option {
+	return func(c *Config) {
+		c.SkipPackages = append(c.SkipPackages, prefixes...)
+	}
+}
+
+// WithCaptureTime задает Config.CaptureTime
+func WithCaptureTime(enable bool) Option {
+	return func(c *Config) {
+		c.CaptureTime = enable
+	}
+}
+
+// WithCaptureGoroutine задает Config.CaptureGoroutine
+func WithCaptureGoroutine(enable bool) Option {
+	return func(c *Config) {
+		c.CaptureGoroutine = enable
+	}
+}
+
+// WithCaptureEnvironment задает Config.CaptureEnvironment
+func WithCaptureEnvironment(enable bool) Option {
+	return func(c *Config) {
+		c.CaptureEnvironment = enable
+	}
+}
+
+// WithTrimSourcePaths задает Config.TrimSourcePaths
+func WithTrimSourcePaths(enable bool) Option {
+	return func(c *Config) {
+		c.TrimSourcePaths = enable
+	}
+}
+
+// WithFormatter задает Config.Formatter. nil возвращает стандартный текст
+func WithFormatter(fn func(e *Error) string) Option {
+	return func(c *Config) {
+		c.Formatter = fn
+	}
+}
+
+// WithHook добавляет функцию в Config.Hooks
+func WithHook(fn func(e *Error)) Option {
+	return func(c *Config) {
+		c.Hooks = append(c.Hooks, fn)
+	}
+}
+
+// WithoutHooks удаляет все хуки
+func WithoutHooks() Option {
+	return func(c *Config) {
+		c.Hooks = nil
+	}
+}
+
+// WithRedact задает Config.Redact
+func WithRedact(fn func(key string, value any) any) Option {
+	return func(c *Config) {
+		c.Redact = fn
+	}
+}
+
+// WithMaxMessageLength задает Config.MaxMessageLength
+func WithMaxMessageLength(n int) Option {
+	return func(c *Config) {
+		c.MaxMessageLength = n
+	}
+}
+
+// WithColor задает Config.Color
+func WithColor(mode ColorMode) Option {
+	return func(c *Config) {
+		c.Color = mode
+	}
+}
+
+// WithMaxChainDepth задает Config.MaxChainDepth
+func WithMaxChainDepth(n int) Option {
+	return func(c *Config) {
+		c.MaxChainDepth = n
+	}
+}
+
+// WithStackSampler задает Config.StackSampler
+func WithStackSampler(s StackSampler) Option {
+	return func(c *Config) {
+		c.StackSampler = s
+	}
+}
+
+// WithForeignCodes задает Config.ForeignCodes
+func WithForeignCodes(enable bool) Option {
+	return func(c *Config) {
+		c.ForeignCodes = enable
+	}
+}
+
+// WithCrossGoroutineStacks задает Config.CrossGoroutineStacks
+func WithCrossGoroutineStacks(enable bool) Option {
+	return func(c *Config) {
+		c.CrossGoroutineStacks = enable
+	}
+}
+
+// WithEnricher добавляет функцию в Config.Enrichers
+func WithEnricher(fn func(ctx context.Context, e *Error)) Option {
+	return func(c *Config) {
+		c.Enrichers = append(c.Enrichers, fn)
+	}
+}
+
+// WithoutEnrichers удаляет все функции Config.Enrichers
+func WithoutEnrichers() Option {
+	return func(c *Config) {
+		c.Enrichers = nil
+	}
+}
+
+// WithSanitize задает Config.Sanitize
+func WithSanitize(s Sanitize) Option {
+	return func(c *Config) {
+		c.Sanitize = s
+	}
+}
@@ -0,0 +1,127 @@
+package nerr
+
+import (
+	"context"
+	"time"
+)
+
+// OpPathSeparator разделяет элементы пути операций, накопленного через PushOp
+const OpPathSeparator = "/"
+
+// Поля ошибки, созданной NewCtx, описывающие состояние контекста
+const (
+	// FieldContextCanceled - контекст уже был отменен (в том числе по истечении срока) при создании ошибки
+	FieldContextCanceled = "ctx.canceled"
+	// FieldDeadlineRemaining - время, остававшееся до срока контекста. Отрицательное, если срок истек
+	FieldDeadlineRemaining = "ctx.deadline_remaining"
+)
+
+type (
+	opPathKey struct{}
+	fieldsKey struct{}
+)
+
+var contextExtractors registry[func(ctx context.Context) map[string]any]
+
+// RegisterContextExtractor регистрирует функцию, извлекающую из контекста поля для ошибок, созданных через NewCtx.
+// Вызывать при инициализации
+func RegisterContextExtractor(fn func(ctx context.Context) map[string]any) {
+	contextExtractors.add(fn)
+}
+
+// PushOp возвращает контекст, в котором op добавлен к пути операций
+func PushOp(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, opPathKey{}, joinOpPath(OpPath(ctx), op))
+}
+
+// OpPath возвращает путь операций, накопленный в контексте через PushOp
+func OpPath(ctx context.Context) string {
+	path, _ := ctx.Value(opPathKey{}).(string)
+	return path
+}
+
+// ContextFields возвращает контекст с добавленными полями. NewCtx прикрепляет их к каждой создаваемой ошибке
+func ContextFields(ctx context.Context, fields map[string]any) context.Context {
+	parent := FieldsFromContext(ctx)
+
+	merged := make(map[string]any, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext возвращает поля, добавленные в контекст через ContextFields
+func FieldsFromContext(ctx context.Context) map[string]any {
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]any)
+	return fields
+}
+
+// NewCtx аналог New, дополняющий ошибку полями из контекста и сведениями о его состоянии: FieldContextCanceled,
+// если контекст отменен, и FieldDeadlineRemaining, если у него есть срок. По ним видно, был ли бюджет времени
+// исчерпан до вызова или в нем. Op ошибки предваряется путем операций из PushOp
+func NewCtx(ctx context.Context, args ...any) error {
+	e := newCtxError(2, ctx, args)
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
+}
+
+// newCtxError создает ошибку NewCtx без вызова хуков. codeLevel - как в newError относительно вызвавшей функции
+func newCtxError(codeLevel int, ctx context.Context, args []any) *Error {
+	if codeLevel != noTrace {
+		codeLevel++
+	}
+
+	e := newError(codeLevel, append([]any{ctx}, args...))
+	if e == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		setField(e, FieldContextCanceled, true)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		setField(e, FieldDeadlineRemaining, time.Until(deadline))
+	}
+
+	if path := OpPath(ctx); len(path) > 0 {
+		e.Op = joinOpPath(path, e.Op)
+	}
+
+	runEnrichers(ctx, e)
+	return e
+}
+
+func joinOpPath(path, op string) string {
+	if len(path) == 0 {
+		return op
+	}
+	if len(op) == 0 {
+		return path
+	}
+	return path + OpPathSeparator + op
+}
+
+func prepareContext(e *Error, ctx context.Context) {
+	if cfg().CaptureGoroutine {
+		e.Labels = contextLabels(ctx)
+	}
+
+	for k, v := range FieldsFromContext(ctx) {
+		setField(e, k, v)
+	}
+
+	for _, fn := range contextExtractors.items() {
+		for k, v := range fn(ctx) {
+			setField(e, k, v)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package nerr_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestRegisterContextExtractorConcurrent(t *testing.T) {
+	const n = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		key := "test.extractor." + strconv.Itoa(i)
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			nerr.RegisterContextExtractor(func(context.Context) map[string]any {
+				return map[string]any{key: true}
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = nerr.NewCtx(context.Background(), "op")
+		}()
+	}
+	wg.Wait()
+
+	err := nerr.NewCtx(context.Background(), "op")
+	for i := 0; i < n; i++ {
+		key := "test.extractor." + strconv.Itoa(i)
+		if v, ok := nerr.Field(err, key); !ok || v != true {
+			t.Fatalf("field %s = %v, %v", key, v, ok)
+		}
+	}
+}
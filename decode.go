@@ -0,0 +1,45 @@
+package nerr
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Поля ошибки, оборачивающей ошибку разбора JSON
+const (
+	FieldJSONOffset   = "json.offset"
+	FieldJSONField    = "json.field"
+	FieldJSONExpected = "json.expected"
+	FieldJSONValue    = "json.value"
+)
+
+func init() {
+	MapError(IsJSONDecode, ErrValidation)
+}
+
+// IsJSONDecode проверяет, что в цепочке есть ошибка разбора JSON: *json.SyntaxError или *json.UnmarshalTypeError
+func IsJSONDecode(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// addJSONFields добавляет в ошибку позицию и поле, если она непосредственно
+// (без промежуточных *Error) оборачивает ошибку разбора JSON
+func addJSONFields(e *Error) {
+	if v, ok := directCause[*json.SyntaxError](e); ok {
+		setField(e, FieldJSONOffset, v.Offset)
+		return
+	}
+
+	if v, ok := directCause[*json.UnmarshalTypeError](e); ok {
+		setField(e, FieldJSONOffset, v.Offset)
+		if len(v.Field) > 0 {
+			setField(e, FieldJSONField, v.Field)
+		}
+		if v.Type != nil {
+			setField(e, FieldJSONExpected, v.Type.String())
+		}
+		setField(e, FieldJSONValue, v.Value)
+	}
+}
@@ -0,0 +1,117 @@
+package nerr
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// CodeInfo - описание кода ошибки, зарегистрированного через Define
+type CodeInfo struct {
+	Code int
+	Name string
+	// Place - функция и позиция вызова Define
+	Place string
+}
+
+var (
+	definedMu sync.Mutex
+	defined   []CodeInfo
+)
+
+func init() {
+	for code, name := range map[int]string{
+		ErrValidation:          "validation",
+		ErrNotFound:            "not_found",
+		ErrTimeout:             "timeout",
+		ErrCanceled:            "canceled",
+		ErrUnavailable:         "unavailable",
+		ErrConflict:            "conflict",
+		ErrRateLimited:         "rate_limited",
+		ErrPermissionDenied:    "permission_denied",
+		ErrEOF:                 "eof",
+		ErrUnexpectedEOF:       "unexpected_eof",
+		ErrCertificateInvalid:  "certificate_invalid",
+		ErrCertificateHostname: "certificate_hostname",
+		ErrTLSHandshakeTimeout: "tls_handshake_timeout",
+		ErrTxBegin:             "tx_begin",
+		ErrTxCommit:            "tx_commit",
+		ErrTxRollback:          "tx_rollback",
+		ErrPanic:               "panic",
+		ErrNotImplemented:      "not_implemented",
+		ErrUnsupported:         "unsupported",
+	} {
+		defined = append(defined, CodeInfo{Code: code, Name: name, Place: "github.com/n-r-w/nerr"})
+	}
+}
+
+// Define регистрирует код ошибки с именем name и возвращает code:
+//
+//	var ErrUserNotFound = nerr.Define(5001, "user_not_found")
+//
+// Совпадение значений кодов с разными именами проверяет Validate
+func Define(code int, name string) int {
+	info := CodeInfo{Code: code, Name: name}
+	if frames := callers(1); len(frames) > 0 {
+		info.Place = frames[0].String()
+	}
+
+	definedMu.Lock()
+	defined = append(defined, info)
+	definedMu.Unlock()
+
+	return code
+}
+
+// Codes возвращает зарегистрированные коды, упорядоченные по значению
+func Codes() []CodeInfo {
+	definedMu.Lock()
+	res := make([]CodeInfo, len(defined))
+	copy(res, defined)
+	definedMu.Unlock()
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Code < res[j].Code })
+	return res
+}
+
+// CodeName возвращает имя кода, заданное в Define, или пустую строку
+func CodeName(code int) string {
+	definedMu.Lock()
+	defer definedMu.Unlock()
+
+	for _, info := range defined {
+		if info.Code == code {
+			return info.Name
+		}
+	}
+	return ""
+}
+
+// Validate проверяет, что одно значение кода не зарегистрировано под разными именами.
+// Вызывать при запуске приложения, после инициализации всех пакетов
+func Validate() error {
+	var c Collector
+
+	first := make(map[int]CodeInfo)
+	for _, info := range Codes() {
+		prev, ok := first[info.Code]
+		if !ok {
+			first[info.Code] = info
+			continue
+		}
+
+		if prev.Name != info.Name {
+			c.Add(fmt.Errorf("nerr: code %d defined as %q (%s) and %q (%s)",
+				info.Code, prev.Name, prev.Place, info.Name, info.Place))
+		}
+	}
+
+	return c.Err()
+}
+
+// MustValidate вызывает панику, если Validate вернул ошибку
+func MustValidate() {
+	if err := Validate(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,235 @@
+package nerr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// diffLine - сравниваемый атрибут уровня: key - имя атрибута, value - значение
+type diffLine struct {
+	key   string
+	value string
+}
+
+// diffLevel - уровень цепочки для Diff
+type diffLevel struct {
+	// id - признак, по которому сопоставляются уровни цепочек: операция, код или вид уровня
+	id    string
+	attrs []diffLine
+	// children - цепочки ошибок MultiError
+	children [][]diffLevel
+}
+
+// Diff сравнивает цепочки ошибок по операциям, кодам, полям и текстам сторонних ошибок каждого уровня и возвращает
+// построчную разницу в формате, близком к unified diff. Место возникновения, стек, время и горутина не сравниваются.
+// Уровни сопоставляются по операциям (для уровней без операции - по кодам) как в unified diff: лишний или
+// недостающий уровень обертки выводится отдельными строками, не меняя сравнение остальных уровней. Разница атрибутов
+// уровня выводится вместе. Строки want нумеруются уровнями want, добавленные строки got - уровнями got.
+// Для совпадающих цепочек возвращается пустая строка
+func Diff(want, got error) string {
+	var b strings.Builder
+	if !diffChains(&b, diffLevels(want), diffLevels(got), "") {
+		return ""
+	}
+	return "--- want\n+++ got\n" + b.String()
+}
+
+// diffChains выводит разницу цепочек want и got и сообщает, есть ли она
+func diffChains(b *strings.Builder, want, got []diffLevel, prefix string) bool {
+	var (
+		changed bool
+		i, j    int
+	)
+	for _, m := range matchLevels(want, got) {
+		for ; i < m[0]; i++ {
+			writeLevel(b, "- ", want[i], prefix, i)
+			changed = true
+		}
+		for ; j < m[1]; j++ {
+			writeLevel(b, "+ ", got[j], prefix, j)
+			changed = true
+		}
+		if diffLevelAttrs(b, want[i], got[j], prefix, i, j) {
+			changed = true
+		}
+		i++
+		j++
+	}
+	for ; i < len(want); i++ {
+		writeLevel(b, "- ", want[i], prefix, i)
+		changed = true
+	}
+	for ; j < len(got); j++ {
+		writeLevel(b, "+ ", got[j], prefix, j)
+		changed = true
+	}
+	return changed
+}
+
+// matchLevels возвращает пары индексов уровней want и got с одинаковым id, образующие наибольшую общую
+// подпоследовательность
+func matchLevels(want, got []diffLevel) [][2]int {
+	// lcs[i][j] - длина общей подпоследовательности want[i:] и got[j:]
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			switch {
+			case want[i].id == got[j].id:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var res [][2]int
+	for i, j := 0, 0; i < len(want) && j < len(got); {
+		switch {
+		case want[i].id == got[j].id:
+			res = append(res, [2]int{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return res
+}
+
+// diffLevelAttrs выводит атрибуты сопоставленных уровней: совпадающие, затем измененные и отсутствующие в got
+// на месте атрибута want, затем добавленные в got
+func diffLevelAttrs(b *strings.Builder, want, got diffLevel, prefix string, wantIndex, gotIndex int) bool {
+	var changed bool
+
+	gotValues := make(map[string]string, len(got.attrs))
+	for _, a := range got.attrs {
+		gotValues[a.key] = a.value
+	}
+	wantKeys := make(map[string]bool, len(want.attrs))
+
+	for _, a := range want.attrs {
+		wantKeys[a.key] = true
+
+		v, ok := gotValues[a.key]
+		switch {
+		case ok && v == a.value:
+			writeAttr(b, "  ", prefix, wantIndex, a.key, a.value)
+		case ok:
+			writeAttr(b, "- ", prefix, wantIndex, a.key, a.value)
+			writeAttr(b, "+ ", prefix, gotIndex, a.key, v)
+			changed = true
+		default:
+			writeAttr(b, "- ", prefix, wantIndex, a.key, a.value)
+			changed = true
+		}
+	}
+	for _, a := range got.attrs {
+		if !wantKeys[a.key] {
+			writeAttr(b, "+ ", prefix, gotIndex, a.key, a.value)
+			changed = true
+		}
+	}
+
+	// ошибки MultiError сопоставляются по индексу
+	for k := 0; k < len(want.children) || k < len(got.children); k++ {
+		childPrefix := prefix + strconv.Itoa(wantIndex) + "." + strconv.Itoa(k) + "."
+		switch {
+		case k >= len(got.children):
+			writeChain(b, "- ", want.children[k], childPrefix)
+			changed = true
+		case k >= len(want.children):
+			writeChain(b, "+ ", got.children[k], childPrefix)
+			changed = true
+		default:
+			if diffChains(b, want.children[k], got.children[k], childPrefix) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+func writeChain(b *strings.Builder, mark string, levels []diffLevel, prefix string) {
+	for i, l := range levels {
+		writeLevel(b, mark, l, prefix, i)
+	}
+}
+
+func writeLevel(b *strings.Builder, mark string, l diffLevel, prefix string, index int) {
+	for _, a := range l.attrs {
+		writeAttr(b, mark, prefix, index, a.key, a.value)
+	}
+	for k, child := range l.children {
+		writeChain(b, mark, child, prefix+strconv.Itoa(index)+"."+strconv.Itoa(k)+".")
+	}
+}
+
+func writeAttr(b *strings.Builder, mark, prefix string, index int, key, value string) {
+	b.WriteString(mark + "[" + prefix + strconv.Itoa(index) + "] " + key + value + "\n")
+}
+
+// diffLevels возвращает уровни цепочки err, начиная с внешнего
+func diffLevels(err error) []diffLevel {
+	if err == nil {
+		return []diffLevel{{id: "nil", attrs: []diffLine{{value: "<nil>"}}}}
+	}
+
+	var res []diffLevel
+	for err != nil {
+		switch v := err.(type) {
+		case *Error:
+			l := diffLevel{id: "code: " + strconv.Itoa(v.Code)}
+			op := v.Operation()
+			if len(op) > 0 {
+				l.id = "op: " + op
+			}
+
+			if len(op) == 0 && v.Code == 0 && len(v.Fields) == 0 {
+				l.attrs = append(l.attrs, diffLine{value: "error"})
+			}
+			if len(op) > 0 {
+				l.attrs = append(l.attrs, diffLine{key: "op: ", value: op})
+			}
+			if v.Code != 0 {
+				l.attrs = append(l.attrs, diffLine{key: "code: ", value: strconv.Itoa(v.Code)})
+			}
+
+			fields := make([]string, 0, len(v.Fields))
+			for k := range v.Fields {
+				fields = append(fields, k)
+			}
+			sort.Strings(fields)
+			for _, k := range fields {
+				l.attrs = append(l.attrs, diffLine{key: "field " + k + ": ", value: fmt.Sprintf("%v", v.Fields[k])})
+			}
+
+			res = append(res, l)
+			err = v.Err
+
+		case *MultiError:
+			l := diffLevel{id: "multi"}
+			for _, child := range v.Errors {
+				l.children = append(l.children, diffLevels(child))
+			}
+			if v.Dropped > 0 {
+				l.attrs = append(l.attrs, diffLine{key: "dropped: ", value: strconv.Itoa(v.Dropped)})
+			}
+			return append(res, l)
+
+		default:
+			return append(res, diffLevel{id: "message", attrs: []diffLine{{key: "message: ", value: err.Error()}}})
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,124 @@
+package nerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestDiff(t *testing.T) {
+	boom := errors.New("boom")
+	inner := func() error { return nerr.New("inner", 5001, boom) }
+
+	tests := []struct {
+		name string
+		want error
+		got  error
+		diff string
+	}{
+		{
+			name: "equal",
+			want: nerr.New("outer", inner()),
+			got:  nerr.New("outer", inner()),
+		},
+		{
+			name: "both nil",
+		},
+		{
+			name: "extra wrapper",
+			want: inner(),
+			got:  nerr.New("outer", inner()),
+			diff: `--- want
++++ got
++ [0] op: outer
+  [0] op: inner
+  [0] code: 5001
+  [1] message: boom
+`,
+		},
+		{
+			name: "missing wrapper",
+			want: nerr.New("service", nerr.New("repo", inner())),
+			got:  nerr.New("service", inner()),
+			diff: `--- want
++++ got
+  [0] op: service
+- [1] op: repo
+  [2] op: inner
+  [2] code: 5001
+  [3] message: boom
+`,
+		},
+		{
+			name: "changed level",
+			want: nerr.New("outer", nerr.ErrNotFound, map[string]any{"id": 1}, inner()),
+			got:  nerr.New("outer", nerr.ErrConflict, map[string]any{"id": 1, "user": "x"}, inner()),
+			diff: `--- want
++++ got
+  [0] op: outer
+- [0] code: 9001
++ [0] code: 9005
+  [0] field id: 1
++ [0] field user: x
+  [1] op: inner
+  [1] code: 5001
+  [2] message: boom
+`,
+		},
+		{
+			name: "changed message",
+			want: nerr.New("op", errors.New("a")),
+			got:  nerr.New("op", errors.New("b")),
+			diff: `--- want
++++ got
+  [0] op: op
+- [1] message: a
++ [1] message: b
+`,
+		},
+		{
+			name: "replaced op",
+			want: nerr.New("load", boom),
+			got:  nerr.New("save", boom),
+			diff: `--- want
++++ got
+- [0] op: load
++ [0] op: save
+  [1] message: boom
+`,
+		},
+		{
+			name: "nil",
+			want: nil,
+			got:  nerr.New("op"),
+			diff: `--- want
++++ got
+- [0] <nil>
++ [0] op: op
+`,
+		},
+		{
+			name: "multi",
+			want: nerr.New("batch", &nerr.MultiError{Errors: []error{nerr.New("a", boom), nerr.New("b", boom)}}),
+			got:  nerr.New("batch", &nerr.MultiError{Errors: []error{nerr.New("a", boom), nerr.New("wrap", nerr.New("b", boom))}}),
+			diff: `--- want
++++ got
+  [0] op: batch
+  [1.0.0] op: a
+  [1.0.1] message: boom
++ [1.1.0] op: wrap
+  [1.1.0] op: b
+  [1.1.1] message: boom
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nerr.Diff(tt.want, tt.got); got != tt.diff {
+				t.Fatalf("Diff() =\n%s\nwant\n%s", got, tt.diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package nerr
+
+import "time"
+
+// FieldDuration - поле с длительностью операции, завершившейся ошибкой
+const FieldDuration = "duration"
+
+// WithDuration задает длительность операции, завершившейся ошибкой, в поле FieldDuration.
+// Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithDuration(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+	setField(e, FieldDuration, d)
+	return e
+}
+
+// Duration возвращает длительность операции из поля FieldDuration
+func Duration(err error) (time.Duration, bool) {
+	v, _ := Field(err, FieldDuration)
+	d, ok := v.(time.Duration)
+	return d, ok
+}
+
+// Timed возвращает функцию, которая выполняет fn и при ошибке оборачивает ее операцией op
+// с длительностью выполнения в поле FieldDuration:
+//
+//	err := nerr.Timed("store.load")(func() error { return s.load(ctx) })
+func Timed(op string) func(fn func() error) error {
+	return func(fn func() error) error {
+		start := time.Now()
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		elapsed := time.Since(start)
+
+		e := newError(2, []any{op, err})
+		if e == nil {
+			return nil
+		}
+		setField(e, FieldDuration, elapsed)
+
+		runHooks(e)
+		return e
+	}
+}
@@ -0,0 +1,42 @@
+package nerr
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	environmentOnce sync.Once
+	environment     map[string]any
+)
+
+// CaptureEnvironment включает добавление в Fields новой ошибки имени хоста (host), PID процесса (pid)
+// и ревизии VCS, из которой собран бинарник (vcs.revision) (см. WithCaptureEnvironment). Поля добавляются только
+// уровню, который не оборачивает другую ошибку nerr, - окружение одно для всей цепочки. Поля, переданные в New,
+// не заменяются
+func CaptureEnvironment(enable bool) {
+	Configure(WithCaptureEnvironment(enable))
+}
+
+func environmentFields() map[string]any {
+	environmentOnce.Do(func() {
+		environment = map[string]any{
+			"pid": os.Getpid(),
+		}
+
+		if host, err := os.Hostname(); err == nil {
+			environment["host"] = host
+		}
+
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range info.Settings {
+				if s.Key == "vcs.revision" {
+					environment["vcs.revision"] = s.Value
+				}
+			}
+		}
+	})
+
+	return environment
+}
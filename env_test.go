@@ -0,0 +1,37 @@
+package nerr_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestCaptureEnvironment(t *testing.T) {
+	nerr.CaptureEnvironment(true)
+	defer nerr.CaptureEnvironment(false)
+
+	inner := nerr.New("repo", errors.New("boom"))
+	outer := nerr.New("service", fmt.Errorf("wrapped: %w", nerr.New("handler", inner)))
+	explicit := nerr.New("op", map[string]any{"pid": "custom"})
+
+	var withPID int
+	for err := error(outer); err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*nerr.Error); ok {
+			if _, ok := e.Fields["pid"]; ok {
+				withPID++
+			}
+		}
+	}
+	if withPID != 1 {
+		t.Fatalf("pid captured at %d levels, want 1", withPID)
+	}
+	if got, _ := nerr.Field(inner, "pid"); got != os.Getpid() {
+		t.Fatalf("pid = %v, want %d", got, os.Getpid())
+	}
+	if got, _ := nerr.Field(explicit, "pid"); got != "custom" {
+		t.Fatalf("explicit pid = %v", got)
+	}
+}
@@ -0,0 +1,277 @@
+package nerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// EnvelopeLimits - ограничения при разборе представления ошибки (см. Envelope), полученного из недоверенного источника
+type EnvelopeLimits struct {
+	// MaxSize - максимальный размер данных в байтах
+	MaxSize int
+	// MaxDepth - максимальная вложенность JSON, включая цепочку err и значения полей.
+	// Для ErrorFromEnvelope - длина цепочки
+	MaxDepth int
+	// MaxItems - максимальное количество элементов stack, labels, fields и validation на одном уровне
+	MaxItems int
+	// MaxString - максимальная длина строковых значений
+	MaxString int
+}
+
+// DefaultEnvelopeLimits - ограничения, используемые ParseEnvelope и (*Error).UnmarshalJSON
+var DefaultEnvelopeLimits = EnvelopeLimits{
+	MaxSize:   64 << 10,
+	MaxDepth:  64,
+	MaxItems:  128,
+	MaxString: 4096,
+}
+
+var (
+	// ErrEnvelopeTooLarge - данные превышают EnvelopeLimits.MaxSize
+	ErrEnvelopeTooLarge = errors.New("nerr: envelope too large")
+	// ErrEnvelopeTooDeep - вложенность превышает EnvelopeLimits.MaxDepth
+	ErrEnvelopeTooDeep = errors.New("nerr: envelope too deep")
+	// ErrEnvelopeInvalid - данные не являются корректным представлением ошибки
+	ErrEnvelopeInvalid = errors.New("nerr: invalid envelope")
+)
+
+// ParseEnvelope восстанавливает цепочку ошибок из JSON-представления с ограничениями DefaultEnvelopeLimits.
+// Сторонние ошибки восстанавливаются как текст, ошибки проверки полей - как ValidationErrors
+func ParseEnvelope(data []byte) (*Error, error) {
+	return ParseEnvelopeLimits(data, DefaultEnvelopeLimits)
+}
+
+// ParseEnvelopeLimits восстанавливает цепочку ошибок из JSON-представления с заданными ограничениями.
+// Нулевое значение ограничения означает его отсутствие
+func ParseEnvelopeLimits(data []byte, limits EnvelopeLimits) (*Error, error) {
+	if limits.MaxSize > 0 && len(data) > limits.MaxSize {
+		return nil, ErrEnvelopeTooLarge
+	}
+
+	if limits.MaxDepth > 0 {
+		if err := checkEnvelopeDepth(data, limits.MaxDepth); err != nil {
+			return nil, err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var env *Envelope
+	if err := dec.Decode(&env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEnvelopeInvalid, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrEnvelopeInvalid)
+	}
+
+	return ErrorFromEnvelope(env, limits)
+}
+
+// ErrorFromEnvelope восстанавливает цепочку ошибок из представления, полученного другим способом сериализации,
+// проверяя ограничения limits, кроме MaxSize.
+// Представления старых версий приводятся к текущей. Неизвестные поля (Extra) допустимы только в представлениях
+// более новой версии, чем EnvelopeVersion: они сохраняются в уровнях цепочки и возвращаются ToEnvelope
+// вместе с исходной версией. Уровни цепочки отмечаются как полученные из сервиса Envelope.Service (см. Error.Remote)
+func ErrorFromEnvelope(env *Envelope, limits EnvelopeLimits) (*Error, error) {
+	if env == nil {
+		return nil, fmt.Errorf("%w: null", ErrEnvelopeInvalid)
+	}
+	if env.Version < 0 {
+		return nil, fmt.Errorf("%w: version %d", ErrEnvelopeInvalid, env.Version)
+	}
+
+	err, invalid := fromEnvelope(upgradeEnvelope(env), limits, 1, env.Version)
+	if invalid != nil {
+		return nil, invalid
+	}
+
+	if e, ok := err.(*Error); ok {
+		return e, nil
+	}
+	return &Error{Err: err}, nil
+}
+
+// UnmarshalJSON восстанавливает цепочку ошибок, сериализованную MarshalJSON (см. ParseEnvelope)
+func (e *Error) UnmarshalJSON(data []byte) error {
+	res, err := ParseEnvelope(data)
+	if err != nil {
+		return err
+	}
+
+	*e = *res
+	return nil
+}
+
+// checkEnvelopeDepth проверяет вложенность JSON до разбора, чтобы не тратить ресурсы на заведомо недопустимые данные
+func checkEnvelopeDepth(data []byte, maxDepth int) error {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return ErrEnvelopeTooDeep
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// upgradeEnvelope приводит представление старой версии формата к EnvelopeVersion.
+// Версия 0 (до введения версий) совпадает с версией 1, поэтому преобразования пока не требуются
+func upgradeEnvelope(env *Envelope) *Envelope {
+	return env
+}
+
+// envelopeExtra - неизвестные поля уровня из представления более новой версии формата
+type envelopeExtra struct {
+	version int
+	fields  map[string]json.RawMessage
+}
+
+// envelopeExtraOf возвращает неизвестные поля, сохраненные в уровне при разборе, и версию представления
+func envelopeExtraOf(e *Error) (map[string]json.RawMessage, int) {
+	for _, v := range e.values {
+		if extra, ok := v.(envelopeExtra); ok {
+			return extra.fields, extra.version
+		}
+	}
+	return nil, 0
+}
+
+func fromEnvelope(v *Envelope, limits EnvelopeLimits, depth, version int) (error, error) {
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return nil, ErrEnvelopeTooDeep
+	}
+	if err := checkEnvelopeLevel(v, limits); err != nil {
+		return nil, err
+	}
+	if len(v.Extra) > 0 && version <= EnvelopeVersion {
+		keys := make([]string, 0, len(v.Extra))
+		for k := range v.Extra {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("%w: unknown field %q", ErrEnvelopeInvalid, keys[0])
+	}
+
+	isLeaf := len(v.Message) > 0 || len(v.Validation) > 0
+	if isLeaf {
+		if len(v.Op) > 0 || v.Code != 0 || len(v.Place) > 0 || len(v.Stack) > 0 || v.Err != nil {
+			return nil, fmt.Errorf("%w: message with error attributes", ErrEnvelopeInvalid)
+		}
+
+		if len(v.Validation) > 0 {
+			return validationFromMap(v.Validation), nil
+		}
+		return errors.New(v.Message), nil
+	}
+
+	res := &Error{
+		Op:        v.Op,
+		Code:      v.Code,
+		Place:     v.Place,
+		Stack:     v.Stack,
+		Goroutine: v.Goroutine,
+		Labels:    v.Labels,
+		Fields:    v.Fields,
+	}
+	if v.Time != nil {
+		res.Time = *v.Time
+	}
+	res.values = []any{remoteOrigin{service: v.Service}}
+	if len(v.Extra) > 0 {
+		res.values = append(res.values, envelopeExtra{version: version, fields: v.Extra})
+	}
+
+	if v.Err != nil {
+		cause, err := fromEnvelope(v.Err, limits, depth+1, version)
+		if err != nil {
+			return nil, err
+		}
+		res.Err = cause
+	}
+
+	return res, nil
+}
+
+func checkEnvelopeLevel(v *Envelope, limits EnvelopeLimits) error {
+	if limits.MaxItems > 0 {
+		if len(v.Stack) > limits.MaxItems || len(v.Labels) > limits.MaxItems ||
+			len(v.Fields) > limits.MaxItems || len(v.Validation) > limits.MaxItems || len(v.Extra) > limits.MaxItems {
+			return fmt.Errorf("%w: too many items", ErrEnvelopeInvalid)
+		}
+	}
+
+	if limits.MaxString <= 0 {
+		return nil
+	}
+
+	strs := []string{v.Op, v.Place, v.Message, v.Service}
+	for k, val := range v.Extra {
+		strs = append(strs, k, string(val))
+	}
+	for _, f := range v.Stack {
+		strs = append(strs, f.Function, f.File)
+	}
+	for k, val := range v.Labels {
+		strs = append(strs, k, val)
+	}
+	for k, val := range v.Fields {
+		strs = append(strs, k)
+		if s, ok := val.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	for k, messages := range v.Validation {
+		strs = append(strs, k)
+		strs = append(strs, messages...)
+	}
+
+	for _, s := range strs {
+		if len(s) > limits.MaxString {
+			return fmt.Errorf("%w: string too long", ErrEnvelopeInvalid)
+		}
+	}
+
+	return nil
+}
+
+func validationFromMap(m map[string][]string) ValidationErrors {
+	fields := make([]string, 0, len(m))
+	for f := range m {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	var res ValidationErrors
+	for _, f := range fields {
+		for _, msg := range m[f] {
+			res.Add(f, "", msg)
+		}
+	}
+	return res
+}
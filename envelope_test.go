@@ -0,0 +1,125 @@
+package nerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+// fuzzLimits - ограничения разбора в FuzzParseEnvelope, достаточно малые, чтобы фаззер их достигал
+var fuzzLimits = nerr.EnvelopeLimits{
+	MaxSize:   4096,
+	MaxDepth:  8,
+	MaxItems:  4,
+	MaxString: 64,
+}
+
+func FuzzParseEnvelope(f *testing.F) {
+	seeds := []string{
+		`{"op":"a","code":9002,"place":"main.main (main.go:1)","err":{"message":"io"},"version":1}`,
+		`{"op":"a","stack":[{"function":"f","file":"f.go","line":1}],"labels":{"k":"v"},"fields":{"n":1,"s":"x"}}`,
+		`{"op":"a","err":{"op":"b","err":{"op":"c","err":{"validation":{"name":["required"]}}}}}`,
+		`{"op":"a","version":2,"future":{"x":1}}`,
+		`{"op":"a","future":1}`,
+		`{"message":"m","op":"a"}`,
+		`{"op":"a","time":"2024-01-01T00:00:00Z","goroutine":7,"service":"billing"}`,
+		`{"op":"` + strings.Repeat("x", 100) + `"}`,
+		`{"fields":{"a":1,"b":2,"c":3,"d":4,"e":5}}`,
+		strings.Repeat(`{"err":`, 10) + `{"message":"deep"}` + strings.Repeat(`}`, 10),
+		`{"op":"a"} {"op":"b"}`,
+		`{"version":-1}`,
+		`null`,
+		`[]`,
+		`{"op":"a\"}`,
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e, err := nerr.ParseEnvelopeLimits(data, fuzzLimits)
+		if err != nil {
+			if e != nil {
+				t.Fatalf("error %v with non-nil result", err)
+			}
+			if !errors.Is(err, nerr.ErrEnvelopeInvalid) && !errors.Is(err, nerr.ErrEnvelopeTooLarge) &&
+				!errors.Is(err, nerr.ErrEnvelopeTooDeep) {
+				t.Fatalf("unexpected error kind: %v", err)
+			}
+			return
+		}
+
+		if len(data) > fuzzLimits.MaxSize {
+			t.Fatalf("accepted %d bytes", len(data))
+		}
+		checkEnvelopeLimits(t, e)
+
+		// разобранная ошибка должна выводиться и сериализоваться без паники
+		_ = e.Error()
+		_ = nerr.Trace(e)
+		_ = nerr.Pretty(e)
+		out, err := json.Marshal(nerr.ToEnvelope(e))
+		if err != nil {
+			t.Fatalf("marshal parsed error: %v", err)
+		}
+		if _, err := nerr.ParseEnvelopeLimits(out, nerr.EnvelopeLimits{}); err != nil {
+			t.Fatalf("reparse %s: %v", out, err)
+		}
+	})
+}
+
+// checkEnvelopeLimits проверяет, что разобранная цепочка укладывается в fuzzLimits
+func checkEnvelopeLimits(t *testing.T, err error) {
+	t.Helper()
+
+	depth := 0
+	for err != nil {
+		depth++
+		if depth > fuzzLimits.MaxDepth {
+			t.Fatalf("chain depth exceeds %d", fuzzLimits.MaxDepth)
+		}
+
+		e, ok := err.(*nerr.Error)
+		if !ok {
+			return
+		}
+
+		if len(e.Stack) > fuzzLimits.MaxItems || len(e.Labels) > fuzzLimits.MaxItems || len(e.Fields) > fuzzLimits.MaxItems {
+			t.Fatalf("level has too many items: %+v", e)
+		}
+		for _, s := range []string{e.Op, e.Place} {
+			if len(s) > fuzzLimits.MaxString {
+				t.Fatalf("string of %d bytes accepted", len(s))
+			}
+		}
+		err = e.Err
+	}
+}
+
+func TestParseEnvelopeLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{name: "size", data: `{"op":"` + strings.Repeat("x", fuzzLimits.MaxSize) + `"}`, want: nerr.ErrEnvelopeTooLarge},
+		{name: "depth", data: strings.Repeat(`{"err":`, 10) + `{"message":"m"}` + strings.Repeat(`}`, 10), want: nerr.ErrEnvelopeTooDeep},
+		{name: "items", data: `{"fields":{"a":1,"b":2,"c":3,"d":4,"e":5}}`, want: nerr.ErrEnvelopeInvalid},
+		{name: "string", data: `{"op":"` + strings.Repeat("x", fuzzLimits.MaxString+1) + `"}`, want: nerr.ErrEnvelopeInvalid},
+		{name: "unknown field", data: `{"op":"a","future":1}`, want: nerr.ErrEnvelopeInvalid},
+		{name: "trailing data", data: `{"op":"a"} {"op":"b"}`, want: nerr.ErrEnvelopeInvalid},
+		{name: "valid", data: `{"op":"a","err":{"message":"m"}}`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := nerr.ParseEnvelopeLimits([]byte(tt.data), fuzzLimits)
+			if !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
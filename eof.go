@@ -0,0 +1,26 @@
+package nerr
+
+import (
+	"errors"
+	"io"
+)
+
+func init() {
+	MapError(func(err error) bool { return errors.Is(err, io.ErrUnexpectedEOF) }, ErrUnexpectedEOF)
+	MapError(func(err error) bool { return errors.Is(err, io.EOF) }, ErrEOF)
+}
+
+// IsEOF проверяет, что поток закончился штатно: в цепочке есть io.EOF или код ErrEOF
+// и нет признаков обрыва (см. IsUnexpectedEOF)
+func IsEOF(err error) bool {
+	if IsUnexpectedEOF(err) {
+		return false
+	}
+	return errors.Is(err, io.EOF) || HasAnyCode(err, ErrEOF)
+}
+
+// IsUnexpectedEOF проверяет, что поток оборван: в цепочке есть io.ErrUnexpectedEOF или код ErrUnexpectedEOF.
+// Код сохраняется и после передачи ошибки через Envelope, когда исходная ошибка уже недоступна
+func IsUnexpectedEOF(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF) || HasAnyCode(err, ErrUnexpectedEOF)
+}
@@ -0,0 +1,8 @@
+package nerr
+
+// Поля ошибки, оборачивающей *exec.ExitError
+const (
+	FieldExitStatus = "exec.exit_status"
+	FieldSignal     = "exec.signal"
+	FieldStderr     = "exec.stderr"
+)
@@ -0,0 +1,33 @@
+//go:build !nerr_lite
+
+package nerr
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// maxStderrLength - максимальная длина stderr процесса, сохраняемого в FieldStderr
+const maxStderrLength = 1024
+
+// addExecFields добавляет в ошибку сведения о завершении процесса, если она непосредственно
+// (без промежуточных *Error) оборачивает *exec.ExitError
+func addExecFields(e *Error) {
+	exitErr, ok := directCause[*exec.ExitError](e)
+	if !ok || exitErr.ProcessState == nil {
+		return
+	}
+
+	if status := exitErr.ExitCode(); status >= 0 {
+		setField(e, FieldExitStatus, status)
+	} else if state := exitErr.ProcessState.String(); strings.HasPrefix(state, "signal: ") {
+		setField(e, FieldSignal, strings.TrimPrefix(state, "signal: "))
+	}
+
+	if stderr := strings.TrimSpace(string(exitErr.Stderr)); len(stderr) > 0 {
+		if len(stderr) > maxStderrLength {
+			stderr = stderr[:maxStderrLength] + "…"
+		}
+		setField(e, FieldStderr, stderr)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build nerr_lite
+
+package nerr
+
+// addExecFields в режиме nerr_lite не используется: os/exec недоступен на WASM и TinyGo
+func addExecFields(*Error) {}
@@ -0,0 +1,78 @@
+package nerr
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// Коды завершения процесса по умолчанию
+const (
+	// ExitOK - успешное завершение
+	ExitOK = 0
+	// ExitFailure - код завершения для ошибок без заданного соответствия
+	ExitFailure = 1
+)
+
+var (
+	exitCodesMu sync.RWMutex
+	exitCodes   = map[int]int{
+		ErrValidation:  2,   // неверные аргументы, как у большинства утилит
+		ErrTimeout:     124, // как у timeout(1)
+		ErrCanceled:    130, // прерывание по SIGINT
+		ErrUnavailable: 69,  // EX_UNAVAILABLE из sysexits.h
+	}
+)
+
+// RegisterExitCode задает код завершения процесса для кода ошибки. Вызывать при инициализации
+func RegisterExitCode(code, exitCode int) {
+	exitCodesMu.Lock()
+	defer exitCodesMu.Unlock()
+
+	exitCodes[code] = exitCode
+}
+
+// CodeExitCode возвращает код завершения процесса для кода ошибки. Для неизвестных кодов - ExitFailure
+func CodeExitCode(code int) int {
+	exitCodesMu.RLock()
+	defer exitCodesMu.RUnlock()
+
+	if exitCode, ok := exitCodes[code]; ok {
+		return exitCode
+	}
+	return ExitFailure
+}
+
+// ExitCode возвращает код завершения процесса для ошибки по ее коду. Если для кода соответствие не задано,
+// используется код ошибки цепочки с методом ExitCode() int (например *exec.ExitError).
+// Для nil - ExitOK, в остальных случаях - ExitFailure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	exitCodesMu.RLock()
+	exitCode, ok := exitCodes[OutermostCode(err)]
+	exitCodesMu.RUnlock()
+	if ok {
+		return exitCode
+	}
+
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) {
+		// -1 - процесс еще не завершен или завершен сигналом
+		if exitCode := ec.ExitCode(); exitCode > 0 {
+			return exitCode
+		}
+	}
+	return ExitFailure
+}
+
+// Exit завершает процесс с кодом ExitCode(err). Если err не nil, перед завершением
+// в os.Stderr выводится представление FprintPretty
+func Exit(err error) {
+	if err != nil {
+		_ = FprintPretty(os.Stderr, err)
+	}
+	os.Exit(ExitCode(err))
+}
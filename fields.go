@@ -0,0 +1,179 @@
+package nerr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Общепринятые поля ошибок
+const (
+	// FieldUserMessage - сообщение, которое можно показать пользователю
+	FieldUserMessage = "user_message"
+	// FieldUserHint - подсказка пользователю, как исправить ошибку
+	FieldUserHint = "user_hint"
+	// FieldDocURL - ссылка на документацию об ошибке
+	FieldDocURL = "doc_url"
+	// FieldRequestID - идентификатор запроса, при обработке которого возникла ошибка
+	FieldRequestID = "request_id"
+)
+
+// Field возвращает значение поля с ближайшего к внешнему уровня цепочки, на котором оно задано
+func Field(err error, key string) (any, bool) {
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok := e.Fields[key]; ok {
+			return v, true
+		}
+		err = e.Err
+	}
+	return nil, false
+}
+
+// AllFields возвращает поля всех уровней цепочки. При совпадении ключей приоритет у внешнего уровня
+func AllFields(err error) map[string]any {
+	var res map[string]any
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			break
+		}
+
+		for k, v := range e.Fields {
+			if res == nil {
+				res = make(map[string]any)
+			}
+			if _, exists := res[k]; !exists {
+				res[k] = v
+			}
+		}
+		err = e.Err
+	}
+	return res
+}
+
+// UserMessage возвращает сообщение для пользователя из поля FieldUserMessage или, если оно не задано,
+// сообщение по умолчанию для кода ошибки (см. RegisterCodeMessage)
+func UserMessage(err error) string {
+	return userText(err, FieldUserMessage, func(m CodeMessage) string { return m.Message })
+}
+
+// UserHint возвращает подсказку для пользователя из поля FieldUserHint или подсказку по умолчанию для кода ошибки
+func UserHint(err error) string {
+	return userText(err, FieldUserHint, func(m CodeMessage) string { return m.Hint })
+}
+
+// DocURL возвращает ссылку на документацию из поля FieldDocURL или ссылку по умолчанию для кода ошибки
+func DocURL(err error) string {
+	return userText(err, FieldDocURL, func(m CodeMessage) string { return m.DocURL })
+}
+
+func userText(err error, key string, byCode func(m CodeMessage) string) string {
+	v, _ := Field(err, key)
+	if s, _ := v.(string); len(s) > 0 {
+		return s
+	}
+	if err == nil {
+		return ""
+	}
+	return byCode(LookupCodeMessage(OutermostCode(err)))
+}
+
+// RequestID возвращает идентификатор запроса из поля FieldRequestID
+func RequestID(err error) string {
+	v, _ := Field(err, FieldRequestID)
+	s, _ := v.(string)
+	return s
+}
+
+// WithDocURL задает ссылку на документацию (например runbook) в поле FieldDocURL. Ссылка выводится Pretty и попадает
+// в поле type ответа problem+json. Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithDocURL(err error, url string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+
+	setField(e, FieldDocURL, url)
+	return e
+}
+
+// WithRequestID задает идентификатор запроса в поле FieldRequestID. Поле сохраняется при сериализации
+// и выводится адаптерами журналов под ключом request_id. Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithRequestID(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+
+	setField(e, FieldRequestID, id)
+	return e
+}
+
+func setField(e *Error, key string, value any) {
+	if redact := cfg().Redact; redact != nil {
+		value = redact(key, value)
+	}
+
+	if e.Fields == nil {
+		e.Fields = make(map[string]any)
+	}
+	e.Fields[key] = value
+}
+
+// writeFields выводит поля в виде "key=value,..." в порядке ключей
+func writeFields(b *bytes.Buffer, fields map[string]any) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(SanitizeString(k))
+		b.WriteByte('=')
+
+		switch v := fields[k].(type) {
+		case string:
+			b.WriteString(SanitizeString(v))
+		case int:
+			var buf [20]byte
+			b.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		case bool:
+			b.WriteString(strconv.FormatBool(v))
+		default:
+			if cfg().Sanitize.Enabled {
+				b.WriteString(SanitizeString(fmt.Sprint(v)))
+			} else {
+				fmt.Fprint(b, v)
+			}
+		}
+	}
+}
+
+// directCause ищет в цепочке вложенной ошибки e ошибку типа T, не заходя во вложенные *Error,
+// которые уже обработаны при своем создании
+func directCause[T error](e *Error) (T, bool) {
+	for err := e.Err; err != nil; err = errors.Unwrap(err) {
+		if _, ok := err.(*Error); ok {
+			break
+		}
+		if v, ok := err.(T); ok {
+			return v, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
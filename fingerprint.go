@@ -0,0 +1,34 @@
+package nerr
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// Fingerprint возвращает ключ группировки ошибки, построенный по операциям, кодам и функциям мест возникновения всей цепочки.
+// Номера строк и тексты сторонних ошибок не учитываются, поэтому ключ не меняется от сборки к сборке
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			fmt.Fprintf(h, "%T;", err)
+			break
+		}
+
+		var function string
+		if frames := e.Frames(); len(frames) > 0 {
+			function = frames[0].Function
+		}
+
+		fmt.Fprintf(h, "%s;%d;%s;", e.Operation(), e.Code, function)
+		err = e.Err
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,45 @@
+package nerr
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Поля ошибки, оборачивающей *fs.PathError
+const (
+	FieldFSOp   = "fs.op"
+	FieldFSPath = "fs.path"
+)
+
+func init() {
+	MapError(IsNotExist, ErrNotFound)
+	MapError(IsPermission, ErrPermissionDenied)
+	MapError(IsExist, ErrConflict)
+}
+
+// IsNotExist проверяет, что в цепочке есть fs.ErrNotExist
+func IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// IsPermission проверяет, что в цепочке есть fs.ErrPermission
+func IsPermission(err error) bool {
+	return errors.Is(err, fs.ErrPermission)
+}
+
+// IsExist проверяет, что в цепочке есть fs.ErrExist
+func IsExist(err error) bool {
+	return errors.Is(err, fs.ErrExist)
+}
+
+// addPathFields добавляет в ошибку операцию и путь, если она непосредственно
+// (без промежуточных *Error) оборачивает *fs.PathError
+func addPathFields(e *Error) {
+	pathErr, ok := directCause[*fs.PathError](e)
+	if !ok {
+		return
+	}
+
+	setField(e, FieldFSOp, pathErr.Op)
+	setField(e, FieldFSPath, pathErr.Path)
+}
@@ -1,19 +1,5 @@
 module github.com/n-r-w/nerr
 
-go 1.18
+go 1.19
 
-require (
-	github.com/jackc/pgconn v1.12.1
-	github.com/lib/pq v1.10.6
-	github.com/n-r-w/eno v1.0.1
-)
-
-require (
-	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
-	github.com/jackc/pgio v1.0.0 // indirect
-	github.com/jackc/pgpassfile v1.0.0 // indirect
-	github.com/jackc/pgproto3/v2 v2.3.0 // indirect
-	github.com/jackc/pgservicefile v0.0.0-20200714003250-2b9c44734f2b // indirect
-	golang.org/x/crypto v0.0.0-20210711020723-a769d52b0f97 // indirect
-	golang.org/x/text v0.3.7 // indirect
-)
+require github.com/n-r-w/eno v1.0.1
@@ -0,0 +1,12 @@
+package nerr
+
+// GobEncode сериализует цепочку ошибок для encoding/gob (net/rpc, очереди задач).
+// Используется JSON-представление, поэтому значения Fields не требуют gob.Register
+func (e *Error) GobEncode() ([]byte, error) {
+	return e.MarshalJSON()
+}
+
+// GobDecode восстанавливает цепочку ошибок, сериализованную GobEncode (см. ParseEnvelope)
+func (e *Error) GobDecode(data []byte) error {
+	return e.UnmarshalJSON(data)
+}
@@ -0,0 +1,48 @@
+package nerr
+
+import (
+	"bytes"
+	"sort"
+)
+
+// CaptureGoroutine включает запись идентификатора горутины при создании ошибки, а также меток pprof,
+// если в New передан context.Context (см. WithCaptureGoroutine). В Trace метки выводятся очищенными
+// и обрезанными до 64 символов
+func CaptureGoroutine(enable bool) {
+	Configure(WithCaptureGoroutine(enable))
+}
+
+// crossesGoroutine проверяет, что e оборачивает *Error, созданную в другой горутине
+func crossesGoroutine(e *Error) bool {
+	inner, ok := e.Err.(*Error)
+	return ok && e.Goroutine != 0 && inner.Goroutine != 0 && inner.Goroutine != e.Goroutine
+}
+
+// maxLabelRunes - максимальная длина ключа и значения метки pprof в текстовом выводе
+const maxLabelRunes = 64
+
+// writeLabels выводит метки в виде "key=value,..." в порядке ключей. Метки задает приложение, и они могут содержать
+// данные запроса, поэтому ключи и значения очищаются всегда, независимо от Config.Sanitize.Enabled, и обрезаются
+// до maxLabelRunes символов
+func writeLabels(b *bytes.Buffer, labels map[string]string) {
+	opts := cfg().Sanitize
+	opts.Enabled = true
+	if opts.MaxRunes <= 0 || opts.MaxRunes > maxLabelRunes {
+		opts.MaxRunes = maxLabelRunes
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sanitize(opts, k))
+		b.WriteByte('=')
+		b.WriteString(sanitize(opts, labels[k]))
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !nerr_lite
+
+package nerr
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+)
+
+// goroutineID извлекает идентификатор текущей горутины из заголовка runtime.Stack: "goroutine 123 [running]:"
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+func contextLabels(ctx context.Context) map[string]string {
+	var res map[string]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if res == nil {
+			res = make(map[string]string)
+		}
+		res[key] = value
+		return true
+	})
+	return res
+}
@@ -0,0 +1,16 @@
+//go:build nerr_lite
+
+package nerr
+
+import "context"
+
+// goroutineID в режиме nerr_lite не определяет горутину: на WASM и TinyGo runtime.Stack недоступен.
+// Ошибки создаются с Goroutine = 0, поэтому стеки оборачивания в другой горутине не записываются
+func goroutineID() uint64 {
+	return 0
+}
+
+// contextLabels в режиме nerr_lite не читает метки pprof
+func contextLabels(context.Context) map[string]string {
+	return nil
+}
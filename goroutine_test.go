@@ -0,0 +1,25 @@
+//go:build !nerr_lite
+
+package nerr_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestTraceLabelsBounded(t *testing.T) {
+	nerr.CaptureGoroutine(true)
+	defer nerr.CaptureGoroutine(false)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("path", "/a\nb"+strings.Repeat("x", 100)))
+	trace := strings.Join(nerr.Trace(nerr.New(ctx, "op")), "\n")
+
+	want := "labels: path=/a b" + strings.Repeat("x", 60) + "…"
+	if !strings.Contains(trace, want) {
+		t.Fatalf("%q not found in %q", want, trace)
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/n-r-w/nerr/gqlerr
+
+go 1.19
+
+require (
+	github.com/99designs/gqlgen v0.17.24
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/vektah/gqlparser/v2 v2.5.1
+)
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,63 @@
+// Package gqlerr - представление ошибок nerr в ответах GraphQL (gqlgen)
+package gqlerr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/n-r-w/nerr"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Ключи расширений ошибки GraphQL
+const (
+	ExtensionCode      = "code"
+	ExtensionRequestID = "request_id"
+)
+
+// DefaultMessage - сообщение для ошибок без nerr.UserMessage, чтобы не раскрывать внутренние детали
+var DefaultMessage = "internal error"
+
+// ToGQLError преобразует ошибку в gqlerror.Error: сообщение берется из nerr.UserMessage,
+// код и идентификатор запроса передаются в расширениях
+func ToGQLError(err error) *gqlerror.Error {
+	if err == nil {
+		return nil
+	}
+
+	gqlErr := &gqlerror.Error{}
+	fill(gqlErr, err)
+	return gqlErr
+}
+
+// ErrorPresenter - функция для handler.Server.SetErrorPresenter. Ошибки nerr получают сообщение для пользователя
+// и расширения, остальные обрабатываются graphql.DefaultErrorPresenter
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var e *nerr.Error
+	if !errors.As(err, &e) {
+		return gqlErr
+	}
+
+	fill(gqlErr, e)
+	return gqlErr
+}
+
+func fill(gqlErr *gqlerror.Error, err error) {
+	gqlErr.Message = nerr.UserMessage(err)
+	if len(gqlErr.Message) == 0 {
+		gqlErr.Message = DefaultMessage
+	}
+
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]interface{})
+	}
+	if code := nerr.OutermostCode(err); code != 0 {
+		gqlErr.Extensions[ExtensionCode] = code
+	}
+	if id := nerr.RequestID(err); len(id) > 0 {
+		gqlErr.Extensions[ExtensionRequestID] = id
+	}
+}
@@ -0,0 +1,132 @@
+package grpcerr
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain - значение ErrorInfo.Domain в деталях статуса
+var Domain = "nerr"
+
+// Ключи ErrorInfo.Metadata
+const (
+	MetadataCode = "code"
+	MetadataOp   = "op"
+)
+
+// FieldRetryable - поле ошибки, созданной FromGRPCError по статусу с деталями RetryInfo
+const FieldRetryable = "grpc.retryable"
+
+func init() {
+	nerr.MarkRetryable(func(err error) bool {
+		v, _ := nerr.Field(err, FieldRetryable)
+		retryable, _ := v.(bool)
+		return retryable
+	})
+}
+
+// withDetails добавляет к статусу стандартные детали: ErrorInfo с кодом и внешней операцией,
+// BadRequest для ошибок проверки полей и RetryInfo для временных ошибок (с паузой nerr.RetryAfter, если она задана)
+func withDetails(st *status.Status, err error) *status.Status {
+	code := nerr.OutermostCode(err)
+	if code == 0 {
+		return st
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   reason(code),
+		Domain:   Domain,
+		Metadata: map[string]string{MetadataCode: strconv.Itoa(code)},
+	}
+	for _, op := range nerr.Ops(err) {
+		if len(op) > 0 {
+			info.Metadata[MetadataOp] = op
+			break
+		}
+	}
+	details := []protoiface.MessageV1{info}
+
+	if fields := nerr.ValidationFields(err); len(fields) > 0 {
+		names := make([]string, 0, len(fields))
+		for f := range fields {
+			names = append(names, f)
+		}
+		sort.Strings(names)
+
+		br := &errdetails.BadRequest{}
+		for _, f := range names {
+			for _, msg := range fields[f] {
+				br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: f, Description: msg})
+			}
+		}
+		details = append(details, br)
+	}
+
+	if nerr.IsRetryable(err) {
+		info := &errdetails.RetryInfo{}
+		if d, ok := nerr.RetryAfter(err); ok {
+			info.RetryDelay = durationpb.New(d)
+		}
+		details = append(details, info)
+	}
+
+	res, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st
+	}
+	return res
+}
+
+// reason возвращает ErrorInfo.Reason: имя кода в верхнем регистре (UPPER_SNAKE_CASE) или сам код
+func reason(code int) string {
+	if name := nerr.CodeName(code); len(name) > 0 {
+		return strings.ToUpper(name)
+	}
+	return strconv.Itoa(code)
+}
+
+// statusDetails - сведения для ошибки FromGRPCError
+type statusDetails struct {
+	op     string
+	code   int
+	cause  error
+	fields map[string]any
+}
+
+// apply переносит сведения из стандартных деталей статуса
+func (s *statusDetails) apply(details []any) {
+	for _, d := range details {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			if v.GetDomain() != Domain {
+				continue
+			}
+			if code, err := strconv.Atoi(v.GetMetadata()[MetadataCode]); err == nil {
+				s.code = code
+			}
+			if op := v.GetMetadata()[MetadataOp]; len(op) > 0 {
+				s.op = op
+			}
+		case *errdetails.BadRequest:
+			var validation nerr.ValidationErrors
+			for _, fv := range v.GetFieldViolations() {
+				validation.Add(fv.GetField(), "", fv.GetDescription())
+			}
+			if len(validation) > 0 {
+				s.cause = validation
+			}
+		case *errdetails.RetryInfo:
+			s.fields[FieldRetryable] = true
+			if d := v.GetRetryDelay().AsDuration(); d > 0 {
+				s.fields[nerr.FieldRetryAfter] = d
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/n-r-w/nerr/grpcerr
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	google.golang.org/genproto v0.0.0-20221118155620-16455021b5e6
+	google.golang.org/grpc v1.52.0
+	google.golang.org/protobuf v1.28.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	golang.org/x/net v0.4.0 // indirect
+	golang.org/x/sys v0.3.0 // indirect
+	golang.org/x/text v0.5.0 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
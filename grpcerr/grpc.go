@@ -0,0 +1,172 @@
+// Package grpcerr - преобразование ошибок nerr в статусы gRPC и обратно
+package grpcerr
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/n-r-w/nerr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Поля ошибки, созданной FromGRPCError
+const (
+	FieldCode    = "grpc.code"
+	FieldDetails = "grpc.details"
+)
+
+var (
+	mu       sync.RWMutex
+	toGRPC   = map[int]codes.Code{}
+	fromGRPC = map[codes.Code]int{}
+)
+
+func init() {
+	RegisterCode(nerr.ErrValidation, codes.InvalidArgument)
+	RegisterCode(nerr.ErrNotFound, codes.NotFound)
+	RegisterCode(nerr.ErrTimeout, codes.DeadlineExceeded)
+	RegisterCode(nerr.ErrCanceled, codes.Canceled)
+	RegisterCode(nerr.ErrUnavailable, codes.Unavailable)
+	RegisterCode(nerr.ErrConflict, codes.AlreadyExists)
+	RegisterCode(nerr.ErrRateLimited, codes.ResourceExhausted)
+	RegisterCode(nerr.ErrPermissionDenied, codes.PermissionDenied)
+	RegisterCode(nerr.ErrPanic, codes.Internal)
+	RegisterCode(nerr.ErrNotImplemented, codes.Unimplemented)
+	RegisterCode(nerr.ErrUnsupported, codes.InvalidArgument)
+}
+
+// RegisterCode задает соответствие кода nerr коду gRPC. Первый зарегистрированный код nerr
+// используется и для обратного преобразования. Вызывать при инициализации
+func RegisterCode(code int, grpcCode codes.Code) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	toGRPC[code] = grpcCode
+	if _, ok := fromGRPC[grpcCode]; !ok {
+		fromGRPC[grpcCode] = code
+	}
+}
+
+// Code возвращает код gRPC для ошибки по ее коду nerr. Если для кода соответствие не задано, используется
+// статус ошибки цепочки с методом GRPCStatus() *status.Status. В остальных случаях возвращается codes.Unknown
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	if c, ok := registeredCode(err); ok {
+		return c
+	}
+	if st := foreignStatus(err); st != nil {
+		return st.Code()
+	}
+	return codes.Unknown
+}
+
+func registeredCode(err error) (codes.Code, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := toGRPC[nerr.OutermostCode(err)]
+	return c, ok
+}
+
+// foreignStatus возвращает статус ошибки цепочки с методом GRPCStatus() *status.Status, или nil
+func foreignStatus(err error) *status.Status {
+	var gs interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &gs) {
+		return nil
+	}
+
+	st := gs.GRPCStatus()
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	return st
+}
+
+// NerrCode возвращает код nerr, соответствующий коду gRPC, или 0
+func NerrCode(c codes.Code) int {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return fromGRPC[c]
+}
+
+// Status преобразует ошибку в статус gRPC со стандартными деталями ErrorInfo, BadRequest и RetryInfo.
+// Если для кода ошибки соответствие не задано, а в цепочке есть ошибка с методом GRPCStatus() *status.Status,
+// возвращается ее статус вместе с ее деталями
+func Status(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	if c, ok := registeredCode(err); ok {
+		return withDetails(status.New(c, err.Error()), err)
+	}
+	if st := foreignStatus(err); st != nil {
+		return st
+	}
+	return withDetails(status.New(codes.Unknown, err.Error()), err)
+}
+
+// ToGRPCError преобразует ошибку в ошибку со статусом gRPC
+func ToGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return Status(err).Err()
+}
+
+// FromGRPCError преобразует ошибку со статусом gRPC, полученную клиентом, в *nerr.Error
+// с соответствующим кодом, сообщением и деталями статуса. Код nerr, операция, ошибки проверки полей
+// и признак повторяемости восстанавливаются из деталей, добавленных Status. Ошибки без статуса возвращаются без изменений
+func FromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	d := statusDetails{
+		code:   NerrCode(st.Code()),
+		cause:  errors.New(st.Message()),
+		fields: map[string]any{FieldCode: st.Code().String()},
+	}
+	if details := st.Details(); len(details) > 0 {
+		d.fields[FieldDetails] = details
+		d.apply(details)
+	}
+
+	// сведения из деталей передаются в New, чтобы хуки получили уже восстановленную ошибку
+	return nerr.New(nerr.Skip(1), d.op, d.code, d.fields, d.cause)
+}
+
+// SummaryTrailer возвращает трейлер со сводкой об ошибке (nerr.EncodeSummary) для grpc.SetTrailer
+func SummaryTrailer(err error) metadata.MD {
+	md := metadata.MD{}
+	nerr.EncodeSummary(err, func(key, value string) { md.Set(key, value) })
+	return md
+}
+
+// SummaryFromTrailer восстанавливает ошибку по сводке в трейлере, полученном клиентом через grpc.Trailer, или возвращает nil
+func SummaryFromTrailer(md metadata.MD) error {
+	e := nerr.DecodeSummary(func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	})
+	if e != nil {
+		return e
+	}
+	return nil
+}
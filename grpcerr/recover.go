@@ -0,0 +1,41 @@
+package grpcerr
+
+import (
+	"context"
+
+	"github.com/n-r-w/nerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicMessage - сообщение статуса codes.Internal, которое получает клиент при панике обработчика
+var PanicMessage = "internal error"
+
+// UnaryServerRecover - перехватчик, преобразующий панику обработчика в ошибку nerr.FromPanic. Ошибка передается
+// в nerr.Report, клиент получает codes.Internal с сообщением PanicMessage без подробностей паники
+func UnaryServerRecover(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = recovered(ctx, v)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// StreamServerRecover - потоковый вариант UnaryServerRecover
+func StreamServerRecover(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = recovered(ss.Context(), v)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func recovered(ctx context.Context, v any) error {
+	nerr.Report(ctx, nerr.FromPanic(v))
+	return status.Error(codes.Internal, PanicMessage)
+}
@@ -0,0 +1,37 @@
+package nerr
+
+import "context"
+
+// AddHook регистрирует функцию, вызываемую для каждой созданной ошибки (см. WithHook).
+// Пока хуки не зарегистрированы, их проверка сводится к одному атомарному чтению
+func AddHook(fn func(e *Error)) {
+	Configure(WithHook(fn))
+}
+
+// ResetHooks удаляет все зарегистрированные хуки
+func ResetHooks() {
+	Configure(WithoutHooks())
+}
+
+// AddEnricher регистрирует функцию, дополняющую каждую ошибку, созданную с контекстом (см. WithEnricher).
+// Позволяет добавить общие для развертывания поля (регион, имя и версия сервиса), не меняя места создания ошибок
+func AddEnricher(fn func(ctx context.Context, e *Error)) {
+	Configure(WithEnricher(fn))
+}
+
+// ResetEnrichers удаляет все зарегистрированные функции AddEnricher
+func ResetEnrichers() {
+	Configure(WithoutEnrichers())
+}
+
+func runEnrichers(ctx context.Context, e *Error) {
+	for _, fn := range cfg().Enrichers {
+		fn(ctx, e)
+	}
+}
+
+func runHooks(e *Error) {
+	for _, fn := range cfg().Hooks {
+		fn(e)
+	}
+}
@@ -0,0 +1,154 @@
+package nerr
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// StatusClientClosedRequest - нестандартный статус 499 для запросов, отмененных клиентом
+const StatusClientClosedRequest = 499
+
+var (
+	httpStatusesMu sync.RWMutex
+	httpStatuses   = map[int]int{
+		ErrValidation:          http.StatusBadRequest,
+		ErrNotFound:            http.StatusNotFound,
+		ErrTimeout:             http.StatusGatewayTimeout,
+		ErrCanceled:            StatusClientClosedRequest,
+		ErrUnavailable:         http.StatusServiceUnavailable,
+		ErrConflict:            http.StatusConflict,
+		ErrRateLimited:         http.StatusTooManyRequests,
+		ErrPermissionDenied:    http.StatusForbidden,
+		ErrUnexpectedEOF:       http.StatusBadRequest,
+		ErrCertificateInvalid:  http.StatusBadGateway,
+		ErrCertificateHostname: http.StatusBadGateway,
+		ErrTLSHandshakeTimeout: http.StatusGatewayTimeout,
+		ErrNotImplemented:      http.StatusNotImplemented,
+		ErrUnsupported:         http.StatusBadRequest,
+	}
+
+	userMessagesMu sync.RWMutex
+	userMessages   = map[int]CodeMessage{}
+)
+
+// CodeMessage - сведения для пользователя по умолчанию для кода ошибки
+type CodeMessage struct {
+	// Message - сообщение (см. UserMessage)
+	Message string
+	// Hint - подсказка, как исправить ошибку (см. UserHint)
+	Hint string
+	// DocURL - ссылка на документацию (см. DocURL)
+	DocURL string
+}
+
+// RegisterHTTPStatus задает HTTP статус для кода ошибки. Вызывать при инициализации
+func RegisterHTTPStatus(code, status int) {
+	httpStatusesMu.Lock()
+	defer httpStatusesMu.Unlock()
+
+	httpStatuses[code] = status
+}
+
+// HTTPStatus возвращает HTTP статус для ошибки по ее коду. Если для кода статус не задан, используется статус
+// ошибки цепочки с методом StatusCode() int или HTTPStatus() int. Для nil - 200, в остальных случаях - 500
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	httpStatusesMu.RLock()
+	status, ok := httpStatuses[OutermostCode(err)]
+	httpStatusesMu.RUnlock()
+	if ok {
+		return status
+	}
+
+	if status := foreignHTTPStatus(err); status != 0 {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// foreignHTTPStatus возвращает статус ошибки с методом StatusCode() int или HTTPStatus() int, или 0
+func foreignHTTPStatus(err error) int {
+	var status int
+
+	var sc interface{ StatusCode() int }
+	var hs interface{ HTTPStatus() int }
+	switch {
+	case errors.As(err, &sc):
+		status = sc.StatusCode()
+	case errors.As(err, &hs):
+		status = hs.HTTPStatus()
+	}
+
+	if status < 100 || status > 599 {
+		return 0
+	}
+	return status
+}
+
+// CodeHTTPStatus возвращает HTTP статус для кода ошибки. Для неизвестных кодов - 500
+func CodeHTTPStatus(code int) int {
+	httpStatusesMu.RLock()
+	defer httpStatusesMu.RUnlock()
+
+	if status, ok := httpStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// RegisterUserMessage задает сообщение для клиента по умолчанию для кода ошибки (см. PublicMessage), не меняя
+// подсказку и ссылку на документацию. Вызывать при инициализации
+func RegisterUserMessage(code int, msg string) {
+	userMessagesMu.Lock()
+	defer userMessagesMu.Unlock()
+
+	m := userMessages[code]
+	m.Message = msg
+	userMessages[code] = m
+}
+
+// RegisterDocURL задает ссылку на документацию по умолчанию для кода ошибки (см. DocURL), не меняя сообщение
+// и подсказку. Вызывать при инициализации
+func RegisterDocURL(code int, url string) {
+	userMessagesMu.Lock()
+	defer userMessagesMu.Unlock()
+
+	m := userMessages[code]
+	m.DocURL = url
+	userMessages[code] = m
+}
+
+// RegisterCodeMessage задает сообщение, подсказку и ссылку на документацию по умолчанию для кода ошибки.
+// Они используются UserMessage, UserHint и DocURL, если в ошибке не заданы соответствующие поля. Вызывать при инициализации
+func RegisterCodeMessage(code int, m CodeMessage) {
+	userMessagesMu.Lock()
+	defer userMessagesMu.Unlock()
+
+	userMessages[code] = m
+}
+
+// LookupCodeMessage возвращает сведения, заданные RegisterCodeMessage и RegisterUserMessage для кода ошибки
+func LookupCodeMessage(code int) CodeMessage {
+	userMessagesMu.RLock()
+	defer userMessagesMu.RUnlock()
+
+	return userMessages[code]
+}
+
+// CodeUserMessage возвращает сообщение, заданное RegisterUserMessage или RegisterCodeMessage, или пустую строку
+func CodeUserMessage(code int) string {
+	return LookupCodeMessage(code).Message
+}
+
+// PublicMessage возвращает сообщение, безопасное для передачи клиенту: UserMessage (с учетом сообщения для кода
+// ошибки) или текст HTTP статуса
+func PublicMessage(err error) string {
+	if msg := UserMessage(err); len(msg) > 0 {
+		return msg
+	}
+	return http.StatusText(HTTPStatus(err))
+}
@@ -0,0 +1,206 @@
+package httperr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Поля ошибок исходящих запросов (см. Transport, Do и CheckResponse)
+const (
+	FieldHTTPMethod  = "http.method"
+	FieldHTTPURL     = "http.url"
+	FieldHTTPStatus  = "http.status"
+	FieldHTTPLatency = "http.latency"
+)
+
+// DefaultClientOp - операция ошибок Transport, Do и CheckResponse по умолчанию
+const DefaultClientOp = "http.client"
+
+// redactedValue заменяет значения параметров запроса в FieldHTTPURL
+const redactedValue = "xxx"
+
+// maxErrorBody - сколько байт тела ответа с ошибкой читается для разбора nerr.APIEnvelope. Прочитанное тело также
+// позволяет повторно использовать соединение
+const maxErrorBody = 64 << 10
+
+// StatusError - причина ошибки CheckResponse для ответа с неуспешным статусом
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Transport - http.RoundTripper, преобразующий ошибки транспорта в ошибки nerr с полями FieldHTTPMethod,
+// FieldHTTPURL (без значений параметров запроса) и FieldHTTPLatency.
+// Место возникновения - первый вызов вне net/http, обычно http.Client.Do. http.Client оборачивает ошибку в *url.Error,
+// она доступна через errors.As.
+//
+// Как требует контракт http.RoundTripper, полученный ответ возвращается без ошибки при любом статусе, а его тело
+// не читается. Ответы с неуспешным статусом преобразует в ошибки Do или CheckResponse
+type Transport struct {
+	// Base - исходный транспорт. По умолчанию http.DefaultTransport
+	Base http.RoundTripper
+	// Op - операция ошибок, в том числе ошибок Do и CheckResponse. По умолчанию DefaultClientOp
+	Op string
+	// IsError определяет неуспешные статусы для Do и CheckResponse. По умолчанию - 4xx и 5xx: ответы 3xx нужны
+	// http.Client для перенаправлений и условных запросов
+	IsError func(status int) bool
+}
+
+// NewTransport возвращает Transport поверх base
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// WrapClient подключает Transport к клиенту c, сохраняя его исходный транспорт
+func WrapClient(c *http.Client) *http.Client {
+	c.Transport = NewTransport(c.Transport)
+	return c
+}
+
+// RoundTrip выполняет запрос исходным транспортом. Ошибка возвращается только если ответ не получен
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base().RoundTrip(r)
+	if err != nil {
+		return nil, t.error(r, nil, time.Since(start), err)
+	}
+	return resp, nil
+}
+
+// Do выполняет запрос клиентом c. В отличие от c.Do ответ с неуспешным статусом возвращается как ошибка
+// CheckResponse с полем FieldHTTPLatency, а тело ответа закрывается. Op и IsError берутся из Transport клиента,
+// если он подключен
+func Do(c *http.Client, r *http.Request) (*http.Response, error) {
+	t, _ := c.Transport.(*Transport)
+	if t == nil {
+		t = &Transport{}
+	}
+
+	start := time.Now()
+	resp, err := c.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.checkResponse(resp, time.Since(start)); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CheckResponse возвращает nil для успешного статуса ответа (см. Transport), иначе - ошибку с полями
+// FieldHTTPMethod, FieldHTTPURL и FieldHTTPStatus. Заголовок Retry-After сохраняется в поле nerr.FieldRetryAfter.
+// Тело ответа читается (не более 64 КБ) и закрывается. Если тело содержит nerr.APIEnvelope, причиной становится
+// восстановленная из него ошибка с кодом и сообщением сервиса (nerr.ParseAPIEnvelope), иначе - StatusError
+func CheckResponse(resp *http.Response) error {
+	return (&Transport{}).CheckResponse(resp)
+}
+
+// CheckResponse работает как одноименная функция пакета с настройками Op и IsError транспорта
+func (t *Transport) CheckResponse(resp *http.Response) error {
+	return t.checkResponse(resp, 0)
+}
+
+// checkResponse преобразует ответ с неуспешным статусом в ошибку. Нулевая latency не записывается
+func (t *Transport) checkResponse(resp *http.Response, latency time.Duration) error {
+	if !t.isError(resp.StatusCode) {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	_ = resp.Body.Close()
+
+	return t.error(resp.Request, resp, latency, statusCause(resp.StatusCode, body))
+}
+
+// statusCause возвращает причину ошибки ответа с неуспешным статусом: ошибку из тела в формате nerr.APIEnvelope
+// или StatusError. Тело без кода, сообщения и details не считается APIEnvelope
+func statusCause(status int, body []byte) error {
+	if len(body) > 0 {
+		if e, err := nerr.ParseAPIEnvelope(body); err == nil && (e.Code != 0 || e.Err != nil) {
+			return e
+		}
+	}
+	return &StatusError{StatusCode: status}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) isError(status int) bool {
+	if t.IsError != nil {
+		return t.IsError(status)
+	}
+	return status >= 400
+}
+
+func (t *Transport) error(r *http.Request, resp *http.Response, latency time.Duration, cause error) error {
+	op := t.Op
+	if len(op) == 0 {
+		op = DefaultClientOp
+	}
+
+	fields := make(map[string]any, 5)
+	if r != nil {
+		fields[FieldHTTPMethod] = r.Method
+		fields[FieldHTTPURL] = redactURL(r.URL)
+	}
+	if latency > 0 {
+		fields[FieldHTTPLatency] = latency
+	}
+	if resp != nil {
+		fields[FieldHTTPStatus] = resp.StatusCode
+		if d, ok := ParseRetryAfter(resp.Header); ok {
+			fields[nerr.FieldRetryAfter] = d
+		}
+	}
+
+	return nerr.New(nerr.Skip(callSite()), op, fields, cause)
+}
+
+// callSite возвращает уровень стека первой функции вне net/http и этого пакета относительно вызвавшей функции
+func callSite() int {
+	// уровень 0 - callSite, 1 - вызвавшая функция
+	for skip := 2; ; skip++ {
+		f := nerr.Caller(skip)
+		if len(f.Function) == 0 {
+			return 0
+		}
+		if !strings.HasPrefix(f.Function, "net/http.") && !strings.HasPrefix(f.Function, "github.com/n-r-w/nerr/httperr.") {
+			return skip - 1
+		}
+	}
+}
+
+// redactURL возвращает URL без сведений для входа, фрагмента и значений параметров запроса
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	c := *u
+	c.User = nil
+	c.Fragment, c.RawFragment = "", ""
+
+	if len(c.RawQuery) > 0 {
+		q := c.Query()
+		for k := range q {
+			q[k] = []string{redactedValue}
+		}
+		c.RawQuery = q.Encode()
+	}
+
+	return c.String()
+}
@@ -0,0 +1,117 @@
+package httperr_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/httperr"
+)
+
+func TestTransportErrorBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantCode   int
+		wantStatus bool
+		wantText   string
+	}{
+		{"envelope", http.StatusNotFound, `{"code":9001,"message":"user not found","details":[]}`, nerr.ErrNotFound, false, "user not found"},
+		{"envelope details", http.StatusBadRequest, `{"code":9000,"message":"invalid","details":[{"field":"name","rule":"required","message":"is required"}]}`, nerr.ErrValidation, false, "name"},
+		{"empty body", http.StatusBadGateway, "", 0, true, "unexpected status 502"},
+		{"plain text", http.StatusInternalServerError, "internal error", 0, true, "unexpected status 500"},
+		{"foreign json", http.StatusForbidden, `{"error":"forbidden"}`, 0, true, "unexpected status 403"},
+		{"too large", http.StatusNotFound, `{"code":9001,"message":"` + strings.Repeat("x", 128<<10) + `"}`, 0, true, "unexpected status 404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"?token=secret", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := httperr.Do(httperr.WrapClient(&http.Client{}), req)
+			if err == nil {
+				_ = resp.Body.Close()
+				t.Fatal("want error")
+			}
+
+			var e *nerr.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("want *nerr.Error, got %#v", err)
+			}
+
+			if tt.wantCode != 0 && !nerr.IsCode(e, tt.wantCode) {
+				t.Fatalf("code %d not found in %v", tt.wantCode, err)
+			}
+			var statusErr *httperr.StatusError
+			if errors.As(err, &statusErr) != tt.wantStatus {
+				t.Fatalf("StatusError in chain = %v, want %v: %v", !tt.wantStatus, tt.wantStatus, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Fatalf("%q not found in %q", tt.wantText, err.Error())
+			}
+			if got, _ := nerr.Field(e, httperr.FieldHTTPStatus); got != tt.status {
+				t.Fatalf("%s = %v, want %d", httperr.FieldHTTPStatus, got, tt.status)
+			}
+			if got, _ := nerr.Field(e, httperr.FieldHTTPURL); got != srv.URL+"?token=xxx" {
+				t.Fatalf("%s = %v", httperr.FieldHTTPURL, got)
+			}
+			if _, ok := nerr.Field(e, httperr.FieldHTTPLatency); !ok {
+				t.Fatalf("%s not found", httperr.FieldHTTPLatency)
+			}
+		})
+	}
+}
+
+func TestTransportRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("missing"))
+	}))
+	defer srv.Close()
+
+	// ответ с неуспешным статусом возвращается без ошибки и с непрочитанным телом, как требует http.RoundTripper
+	client := httperr.WrapClient(&http.Client{})
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != "missing" {
+		t.Fatalf("body = %q, %v", body, err)
+	}
+
+	resp, err = client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = httperr.CheckResponse(resp)
+	if !errors.As(err, new(*httperr.StatusError)) {
+		t.Fatalf("CheckResponse() = %v, want StatusError", err)
+	}
+	if got, _ := nerr.Field(err, httperr.FieldHTTPMethod); got != http.MethodGet {
+		t.Fatalf("%s = %v", httperr.FieldHTTPMethod, got)
+	}
+
+	// ошибка транспорта
+	srv.Close()
+	_, err = client.Get(srv.URL)
+	var e *nerr.Error
+	if !errors.As(err, &e) || nerr.OutermostOp(e) != httperr.DefaultClientOp {
+		t.Fatalf("Get() = %v, want nerr error", err)
+	}
+}
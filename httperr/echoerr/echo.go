@@ -0,0 +1,59 @@
+// Package echoerr - обработчик ошибок nerr для github.com/labstack/echo
+package echoerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/httperr"
+)
+
+// ErrorHandler возвращает echo.HTTPErrorHandler, который определяет HTTP статус по коду ошибки,
+// отвечает клиенту httperr.Response в JSON и передает ошибку в logFn. Если logFn не задан, полная трасса пишется в c.Logger()
+func ErrorHandler(logFn func(c echo.Context, err error)) echo.HTTPErrorHandler {
+	if logFn == nil {
+		logFn = logTrace
+	}
+
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		status, resp := response(err)
+		if status >= http.StatusInternalServerError {
+			logFn(c, err)
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = c.JSON(status, resp)
+		}
+		if err != nil {
+			c.Logger().Error(err)
+		}
+	}
+}
+
+func response(err error) (int, httperr.Response) {
+	// ошибки маршрутизации и промежуточных обработчиков echo
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && nerr.OutermostCode(err) == 0 {
+		msg := http.StatusText(httpErr.Code)
+		if s, ok := httpErr.Message.(string); ok {
+			msg = s
+		}
+		return httpErr.Code, httperr.Response{Message: msg}
+	}
+
+	return nerr.HTTPStatus(err), httperr.NewResponse(err)
+}
+
+func logTrace(c echo.Context, err error) {
+	c.Logger().Error(fmt.Sprintf("%v\n\t%s", err, strings.Join(nerr.Trace(err), "\n\t")))
+}
@@ -0,0 +1,23 @@
+module github.com/n-r-w/nerr/httperr/echoerr
+
+go 1.19
+
+require (
+	github.com/labstack/echo/v4 v4.10.0
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require (
+	github.com/labstack/gommon v0.4.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.16 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.2.0 // indirect
+	golang.org/x/net v0.4.0 // indirect
+	golang.org/x/sys v0.3.0 // indirect
+	golang.org/x/text v0.5.0 // indirect
+)
+
+replace github.com/n-r-w/nerr => ../..
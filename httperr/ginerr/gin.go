@@ -0,0 +1,57 @@
+// Package ginerr - обработка ошибок nerr в github.com/gin-gonic/gin
+package ginerr
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/httperr"
+)
+
+// Middleware после выполнения обработчиков собирает ошибки из c.Errors в *nerr.Error с маршрутом в качестве op,
+// передает ее в nerr.Report и, если ответ еще не записан, отвечает клиенту статусом по коду ошибки и httperr.Response в JSON
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+
+		err := wrap(c)
+		nerr.Report(c.Request.Context(), err)
+
+		if c.Writer.Written() {
+			return
+		}
+
+		status := nerr.HTTPStatus(err)
+		if c.Request.Method == http.MethodHead {
+			c.Status(status)
+			return
+		}
+
+		c.JSON(status, httperr.NewResponse(err))
+	}
+}
+
+func wrap(c *gin.Context) error {
+	route := c.FullPath()
+	if len(route) == 0 {
+		route = c.Request.URL.Path
+	}
+	op := c.Request.Method + " " + route
+
+	errs := make([]error, 0, len(c.Errors))
+	for _, ge := range c.Errors {
+		errs = append(errs, ge.Err)
+	}
+
+	var cause error = &nerr.MultiError{Errors: errs}
+	if len(errs) == 1 {
+		cause = errs[0]
+	}
+
+	return nerr.New(op, cause)
+}
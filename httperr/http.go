@@ -0,0 +1,186 @@
+// Package httperr - обработчики net/http, возвращающие ошибки nerr
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/n-r-w/nerr"
+)
+
+// ContentTypeProblem - тип содержимого ответа с ошибкой (RFC 7807)
+const ContentTypeProblem = "application/problem+json"
+
+// Problem - описание ошибки в формате RFC 7807 с расширениями nerr
+type Problem struct {
+	Type      string              `json:"type,omitempty"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      int                 `json:"code,omitempty"`
+	RequestID string              `json:"request_id,omitempty"`
+	Hint      string              `json:"hint,omitempty"`
+	Errors    map[string][]string `json:"errors,omitempty"`
+}
+
+// Response - краткое тело ответа с ошибкой для JSON API. Содержит только сведения, безопасные для клиента
+type Response struct {
+	Code      int    `json:"code,omitempty"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewResponse формирует краткое тело ответа с ошибкой
+func NewResponse(err error) Response {
+	return Response{
+		Code:      nerr.OutermostCode(err),
+		Message:   nerr.PublicMessage(err),
+		RequestID: nerr.RequestID(err),
+	}
+}
+
+// NewProblem формирует описание ошибки для клиента. Внутренние сведения (op, трасса) в него не попадают
+func NewProblem(r *http.Request, err error) Problem {
+	status := nerr.HTTPStatus(err)
+
+	p := Problem{
+		Type:      nerr.DocURL(err),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    nerr.UserMessage(err),
+		Code:      nerr.OutermostCode(err),
+		RequestID: nerr.RequestID(err),
+		Hint:      nerr.UserHint(err),
+		Errors:    nerr.ValidationFields(err),
+	}
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	return p
+}
+
+// WriteError передает ошибку в nerr.Report и отвечает клиенту статусом по коду ошибки и Problem.
+// Пауза nerr.RetryAfter выводится в заголовке Retry-After
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	nerr.Report(r.Context(), err)
+
+	p := NewProblem(r, err)
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	SetRetryAfter(w.Header(), err)
+	w.WriteHeader(p.Status)
+
+	if r.Method != http.MethodHead {
+		_ = json.NewEncoder(w).Encode(p)
+	}
+}
+
+// HandlerFunc - обработчик, возвращающий ошибку вместо самостоятельной записи ответа с ошибкой
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler преобразует обработчик, возвращающий ошибку, в http.Handler
+func Handler(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return HandlerFunc(fn)
+}
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+
+	if err := f(rw, r); err != nil {
+		if rw.written {
+			// ответ уже начат, остается только сообщить об ошибке
+			nerr.Report(r.Context(), err)
+			return
+		}
+		WriteError(w, r, err)
+	}
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap позволяет http.ResponseController добраться до исходного ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// SetRetryAfter задает заголовок Retry-After в секундах (с округлением вверх), если у ошибки есть nerr.RetryAfter
+func SetRetryAfter(h http.Header, err error) {
+	if d, ok := nerr.RetryAfter(err); ok {
+		h.Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+	}
+}
+
+// ParseRetryAfter разбирает заголовок Retry-After: количество секунд или дату HTTP. Возвращает false, если заголовка
+// нет или пауза не положительна
+func ParseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(sec) * time.Second, sec > 0
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		return d, d > 0
+	}
+	return 0, false
+}
+
+// SetSummaryHeaders добавляет в заголовки ответа сводку об ошибке (nerr.EncodeSummary)
+func SetSummaryHeaders(h http.Header, err error) {
+	nerr.EncodeSummary(err, h.Set)
+}
+
+// SummaryFromHeaders восстанавливает ошибку по сводке в заголовках ответа или возвращает nil
+func SummaryFromHeaders(h http.Header) error {
+	if e := nerr.DecodeSummary(h.Get); e != nil {
+		return e
+	}
+	return nil
+}
+
+// Recoverer перехватывает панику обработчика next, передает ошибку nerr.FromPanic в nerr.Report и, если ответ еще
+// не начат, отвечает клиенту статусом 500 без подробностей паники. http.ErrAbortHandler пробрасывается дальше
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := nerr.FromPanic(v)
+			if rw.written {
+				nerr.Report(r.Context(), err)
+				return
+			}
+			WriteError(w, r, err)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
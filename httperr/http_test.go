@@ -0,0 +1,125 @@
+package httperr_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/httperr"
+)
+
+func TestHandler(t *testing.T) {
+	var reported []*nerr.Error
+	nerr.AddReporter(nerr.ReporterFunc(func(_ context.Context, e *nerr.Error) {
+		reported = append(reported, e)
+	}))
+	defer nerr.ResetReporters()
+
+	notFound := nerr.WithRequestID(nerr.New("repo", nerr.ErrNotFound, map[string]any{
+		nerr.FieldUserMessage: "user not found",
+		nerr.FieldRetryAfter:  1500 * time.Millisecond,
+	}), "req-1")
+
+	tests := []struct {
+		name       string
+		method     string
+		handler    func(w http.ResponseWriter, r *http.Request) error
+		wantStatus int
+		wantBody   string
+		wantRetry  string
+		reported   int
+	}{
+		{
+			name:   "ok",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, _ *http.Request) error {
+				_, _ = w.Write([]byte("done"))
+				return nil
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "done",
+		},
+		{
+			name:       "error",
+			method:     http.MethodGet,
+			handler:    func(http.ResponseWriter, *http.Request) error { return notFound },
+			wantStatus: http.StatusNotFound,
+			wantRetry:  "2",
+			reported:   1,
+		},
+		{
+			name:       "head",
+			method:     http.MethodHead,
+			handler:    func(http.ResponseWriter, *http.Request) error { return notFound },
+			wantStatus: http.StatusNotFound,
+			wantRetry:  "2",
+			reported:   1,
+		},
+		{
+			name:   "response started",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, _ *http.Request) error {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("partial"))
+				return notFound
+			},
+			wantStatus: http.StatusAccepted,
+			wantBody:   "partial",
+			reported:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+			rec := httptest.NewRecorder()
+			httperr.Handler(tt.handler).ServeHTTP(rec, httptest.NewRequest(tt.method, "/users/1", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if len(reported) != tt.reported {
+				t.Fatalf("reported %d errors, want %d", len(reported), tt.reported)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+
+			switch {
+			case len(tt.wantBody) > 0:
+				if rec.Body.String() != tt.wantBody {
+					t.Fatalf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+				}
+			case tt.method == http.MethodHead:
+				if rec.Body.Len() != 0 {
+					t.Fatalf("HEAD body = %q", rec.Body.String())
+				}
+			default:
+				if ct := rec.Header().Get("Content-Type"); ct != httperr.ContentTypeProblem {
+					t.Fatalf("Content-Type = %q", ct)
+				}
+
+				var p httperr.Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatal(err)
+				}
+				want := httperr.Problem{
+					Title:     "Not Found",
+					Status:    http.StatusNotFound,
+					Detail:    "user not found",
+					Instance:  "/users/1",
+					Code:      nerr.ErrNotFound,
+					RequestID: "req-1",
+				}
+				if p.Type != "" || p.Title != want.Title || p.Status != want.Status || p.Detail != want.Detail ||
+					p.Instance != want.Instance || p.Code != want.Code || p.RequestID != want.RequestID {
+					t.Fatalf("problem = %+v, want %+v", p, want)
+				}
+			}
+		})
+	}
+}
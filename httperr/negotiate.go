@@ -0,0 +1,81 @@
+package httperr
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Типы содержимого ответа с ошибкой, кроме ContentTypeProblem
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeText = "text/plain; charset=utf-8"
+)
+
+// Negotiate выбирает тип ответа с ошибкой по заголовку Accept: ContentTypeProblem, ContentTypeJSON
+// или ContentTypeText. При отсутствии заголовка или неподдерживаемых типах выбирается ContentTypeJSON
+func Negotiate(r *http.Request) string {
+	if r == nil {
+		return ContentTypeJSON
+	}
+
+	best, bestQ := ContentTypeJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		switch mediaType {
+		case ContentTypeProblem:
+			best, bestQ = ContentTypeProblem, q
+		case ContentTypeJSON, "application/*", "*/*":
+			best, bestQ = ContentTypeJSON, q
+		case "text/plain", "text/*":
+			best, bestQ = ContentTypeText, q
+		}
+	}
+
+	return best
+}
+
+// WriteEnvelope передает ошибку в nerr.Report и отвечает клиенту статусом по коду ошибки и телом
+// в формате, выбранном Negotiate: nerr.APIEnvelope, Problem или текст сообщения
+func WriteEnvelope(w http.ResponseWriter, r *http.Request, err error) {
+	contentType := Negotiate(r)
+	if contentType == ContentTypeProblem {
+		WriteError(w, r, err)
+		return
+	}
+
+	nerr.Report(r.Context(), err)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(nerr.HTTPStatus(err))
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	env := nerr.NewAPIEnvelope(err)
+	if contentType == ContentTypeText {
+		_, _ = w.Write([]byte(env.Message + "\n"))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(env)
+}
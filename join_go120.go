@@ -0,0 +1,11 @@
+//go:build go1.20
+
+package nerr
+
+import (
+	"errors"
+	"reflect"
+)
+
+// joinErrorType - тип результата errors.Join (см. fromJoined)
+var joinErrorType = reflect.TypeOf(errors.Join(errors.New("a"), errors.New("b")))
@@ -0,0 +1,8 @@
+//go:build !go1.20
+
+package nerr
+
+import "reflect"
+
+// joinErrorType - тип результата errors.Join. До Go 1.20 errors.Join нет, поэтому fromJoined ничего не преобразует
+var joinErrorType reflect.Type
@@ -0,0 +1,164 @@
+package nerr
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvelopeVersion - текущая версия формата Envelope. Записывается в поле version внешнего уровня.
+// Представления без версии сформированы до ее введения и разбираются как версия 1
+const EnvelopeVersion = 1
+
+// Envelope - сериализуемое представление уровня цепочки ошибок (см. envelope.schema.json) для обмена ошибками
+// между компонентами. Тело ответа API для клиентов - APIEnvelope.
+// Сторонняя ошибка представлена полями Message и Validation
+type Envelope struct {
+	Op         string              `json:"op,omitempty" msgpack:"op,omitempty"`
+	Code       int                 `json:"code,omitempty" msgpack:"code,omitempty"`
+	Place      string              `json:"place,omitempty" msgpack:"place,omitempty"`
+	Stack      []Frame             `json:"stack,omitempty" msgpack:"stack,omitempty"`
+	Goroutine  uint64              `json:"goroutine,omitempty" msgpack:"goroutine,omitempty"`
+	Labels     map[string]string   `json:"labels,omitempty" msgpack:"labels,omitempty"`
+	Time       *time.Time          `json:"time,omitempty" msgpack:"time,omitempty"`
+	Fields     map[string]any      `json:"fields,omitempty" msgpack:"fields,omitempty"`
+	Message    string              `json:"message,omitempty" msgpack:"message,omitempty"`
+	Validation map[string][]string `json:"validation,omitempty" msgpack:"validation,omitempty"`
+	Err        *Envelope           `json:"err,omitempty" msgpack:"err,omitempty"`
+	// Version - версия формата, задается только у внешнего уровня (см. EnvelopeVersion)
+	Version int `json:"version,omitempty" msgpack:"version,omitempty"`
+	// Service - сервис, в котором создан уровень (см. Config.Service)
+	Service string `json:"service,omitempty" msgpack:"service,omitempty"`
+
+	// Extra - поля уровня, неизвестные этой версии формата. Сохраняются при повторной сериализации
+	Extra map[string]json.RawMessage `json:"-" msgpack:"-"`
+}
+
+// envelopeFields - Envelope без методов сериализации
+type envelopeFields Envelope
+
+// envelopeKeys - ключи JSON, известные этой версии формата
+var envelopeKeys = func() map[string]bool {
+	res := map[string]bool{}
+	t := reflect.TypeOf(Envelope{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			res[name] = true
+		}
+	}
+	return res
+}()
+
+// MarshalJSON сериализует уровень вместе с сохраненными неизвестными полями (Extra)
+func (v Envelope) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(envelopeFields(v))
+	if err != nil || len(v.Extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(v.Extra))
+	for k := range v.Extra {
+		if !envelopeKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key, _ := json.Marshal(k)
+		if len(data) > 2 {
+			data = append(data[:len(data)-1], ',')
+		} else {
+			data = data[:len(data)-1]
+		}
+		data = append(data, key...)
+		data = append(data, ':')
+		data = append(data, v.Extra[k]...)
+		data = append(data, '}')
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON разбирает уровень, сохраняя неизвестные поля в Extra
+func (v *Envelope) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var res envelopeFields
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	for k, val := range raw {
+		if envelopeKeys[k] {
+			continue
+		}
+		if res.Extra == nil {
+			res.Extra = map[string]json.RawMessage{}
+		}
+		res.Extra[k] = val
+	}
+
+	*v = Envelope(res)
+	return nil
+}
+
+// MarshalJSON сериализует всю цепочку ошибок. Сторонние ошибки представлены только текстом
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToEnvelope(e))
+}
+
+// ToEnvelope возвращает сериализуемое представление цепочки ошибок текущей версии формата.
+// Если цепочка содержит неизвестные поля из представления более новой версии, сохраняется его версия
+func ToEnvelope(err error) *Envelope {
+	version := EnvelopeVersion
+	res := toEnvelope(err, &version)
+	if res != nil {
+		res.Version = version
+	}
+	return res
+}
+
+func toEnvelope(err error, version *int) *Envelope {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		return &Envelope{
+			Message:    truncateMessage(err.Error()),
+			Validation: ValidationFields(err),
+		}
+	}
+
+	res := &Envelope{
+		Op:        e.Operation(),
+		Code:      e.Code,
+		Place:     e.Place,
+		Stack:     e.Stack,
+		Goroutine: e.Goroutine,
+		Labels:    e.Labels,
+		Fields:    e.Fields,
+		Service:   e.Service(),
+		Err:       toEnvelope(e.Err, version),
+	}
+	if extra, v := envelopeExtraOf(e); len(extra) > 0 {
+		res.Extra = extra
+		if v > *version {
+			*version = v
+		}
+	}
+	if !e.Time.IsZero() {
+		t := e.Time
+		res.Time = &t
+	}
+
+	return res
+}
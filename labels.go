@@ -0,0 +1,131 @@
+package nerr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OtherLabel - значение метки для операций и кодов, не входящих в MetricLabels.Ops и MetricLabels.Codes
+const OtherLabel = "other"
+
+// IDPlaceholder - замена идентификаторов в операциях при нормализации (см. NormalizeOp)
+const IDPlaceholder = "{id}"
+
+// MetricLabels - ограничения значений меток code и op в метриках ошибок (nerrprom, nerrexpvar), чтобы количество
+// рядов не росло из-за идентификаторов сущностей в операциях. Нулевое значение нормализует операции через NormalizeOp.
+// Метка op всегда очищается от управляющих символов и обрезается до 64 символов
+type MetricLabels struct {
+	// Ops - шаблоны допустимых операций в синтаксисе path.Match (см. IsOp). Сравнивается нормализованная операция,
+	// остальные заменяются OtherLabel. Пусто - все операции
+	Ops []string
+	// Codes - допустимые коды. Остальные заменяются OtherLabel. Пусто - все коды
+	Codes []int
+	// Normalize - нормализация операции. nil - NormalizeOp
+	Normalize func(op string) string
+}
+
+// Op возвращает значение метки op для ошибки: нормализованную внутреннюю операцию или OtherLabel
+func (l MetricLabels) Op(err error) string {
+	op := InnermostOp(err)
+
+	if l.Normalize != nil {
+		op = l.Normalize(op)
+	} else {
+		op = NormalizeOp(op)
+	}
+	op = sanitize(Sanitize{Enabled: true, MaxRunes: maxLabelRunes}, op)
+
+	if len(l.Ops) == 0 || len(op) == 0 {
+		return op
+	}
+	for _, pattern := range l.Ops {
+		if matchOp(op, pattern) {
+			return op
+		}
+	}
+	return OtherLabel
+}
+
+// Code возвращает значение метки code для ошибки: внешний код или OtherLabel
+func (l MetricLabels) Code(err error) string {
+	code := OutermostCode(err)
+
+	if len(l.Codes) == 0 || code == 0 {
+		return strconv.Itoa(code)
+	}
+	for _, c := range l.Codes {
+		if c == code {
+			return strconv.Itoa(code)
+		}
+	}
+	return OtherLabel
+}
+
+// NormalizeOp заменяет IDPlaceholder сегменты операции, похожие на идентификаторы: числа, UUID и длинные
+// шестнадцатеричные строки. Сегменты разделяются любыми символами, кроме букв, цифр, '-' и '_':
+// "user.42.orders/7f9c2ba4e88f827d" -> "user.{id}.orders/{id}"
+func NormalizeOp(op string) string {
+	if len(op) == 0 {
+		return op
+	}
+
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		if segment := op[start:end]; isIDSegment(segment) {
+			b.WriteString(IDPlaceholder)
+		} else {
+			b.WriteString(segment)
+		}
+		start = -1
+	}
+
+	for i, r := range op {
+		if r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		b.WriteRune(r)
+	}
+	flush(len(op))
+
+	return b.String()
+}
+
+// minHexIDLength - минимальная длина шестнадцатеричной строки, которая считается идентификатором
+const minHexIDLength = 12
+
+func isIDSegment(s string) bool {
+	var digits, hex, dashes int
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r >= 'a' && r <= 'f', r >= 'A' && r <= 'F':
+			hex++
+		case r == '-':
+			dashes++
+		default:
+			return false
+		}
+	}
+
+	switch {
+	case digits == len(s):
+		return true
+	case digits == 0:
+		return false
+	case dashes == 4 && len(s) == 36:
+		// UUID
+		return true
+	default:
+		return dashes == 0 && digits+hex >= minHexIDLength
+	}
+}
@@ -0,0 +1,25 @@
+package nerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestMetricLabelsOpBounded(t *testing.T) {
+	tests := []struct {
+		op   string
+		want string
+	}{
+		{"user.42.load", "user.{id}.load"},
+		{"load\nuser", "load user"},
+		{strings.Repeat("a", 100), strings.Repeat("a", 64) + "…"},
+	}
+
+	for _, tt := range tests {
+		if got := (nerr.MetricLabels{}).Op(nerr.New(tt.op)); got != tt.want {
+			t.Fatalf("Op(%q) = %q, want %q", tt.op, got, tt.want)
+		}
+	}
+}
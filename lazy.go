@@ -0,0 +1,47 @@
+package nerr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// lazyMessage - текст операции, форматируемый при первом обращении
+type lazyMessage struct {
+	format string
+	args   []any
+
+	once sync.Once
+	text string
+}
+
+func (m *lazyMessage) String() string {
+	m.once.Do(func() {
+		m.text = fmt.Sprintf(m.format, m.args...)
+		m.args = nil
+	})
+	return m.text
+}
+
+// NewFmtLazy создает ошибку так же, как NewFmt, но текст операции форматируется только при выводе ошибки.
+// Ошибки, которые создаются и отбрасываются на горячих путях (например при повторах), не тратят время на fmt.Sprintf.
+// Поле Op такой ошибки остается пустым, текст возвращает Operation. Аргументы сохраняются по ссылке,
+// поэтому изменять их после вызова не следует
+func NewFmtLazy(format string, args ...any) error {
+	return New(Skip(1), &lazyMessage{format: format, args: args})
+}
+
+// Operation возвращает операцию уровня. В отличие от поля Op учитывает текст NewFmtLazy
+func (e *Error) Operation() string {
+	if len(e.Op) == 0 && e.lazyOp != nil {
+		return e.lazyOp.String()
+	}
+	return e.Op
+}
+
+// resolveLazyOp переносит текст NewFmtLazy в Op перед изменением операции. Вызывается только для нового уровня
+func (e *Error) resolveLazyOp() {
+	if e.lazyOp != nil {
+		e.Op = e.Operation()
+		e.lazyOp = nil
+	}
+}
@@ -0,0 +1,63 @@
+package nerr_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+// countingArg считает обращения к String
+type countingArg struct {
+	calls int
+}
+
+func (c *countingArg) String() string {
+	c.calls++
+	return "user"
+}
+
+func TestNewFmtLazy(t *testing.T) {
+	// обе ошибки создаются в одной строке, чтобы место возникновения совпадало
+	create := func(newFmt func(format string, args ...any) error, arg any) error {
+		return nerr.New("service", newFmt("load %v %d", arg, 42))
+	}
+
+	arg := &countingArg{}
+	lazy := create(nerr.NewFmtLazy, arg)
+	if arg.calls != 0 {
+		t.Fatalf("arguments formatted before rendering: %d calls", arg.calls)
+	}
+
+	eager := create(nerr.NewFmt, "user")
+
+	if lazy.Error() != eager.Error() {
+		t.Fatalf("Error() = %q, want %q", lazy.Error(), eager.Error())
+	}
+	if got, want := nerr.Ops(lazy), nerr.Ops(eager); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ops() = %v, want %v", got, want)
+	}
+	if !nerr.IsOp(lazy, "load user 42") {
+		t.Fatal("IsOp() = false")
+	}
+	if d := nerr.Diff(eager, lazy); d != "" {
+		t.Fatalf("Diff() =\n%s", d)
+	}
+
+	var e *nerr.Error
+	if !errors.As(errors.Unwrap(lazy), &e) || e.Op != "" || e.Operation() != "load user 42" {
+		t.Fatalf("Op = %q, Operation() = %q", e.Op, e.Operation())
+	}
+
+	// текст форматируется один раз
+	_ = nerr.Ops(lazy)
+	if arg.calls != 1 {
+		t.Fatalf("arguments formatted %d times, want 1", arg.calls)
+	}
+
+	added := e.AddOp("retry")
+	if added.Op != "load user 42, retry" {
+		t.Fatalf("AddOp() Op = %q", added.Op)
+	}
+}
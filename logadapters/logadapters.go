@@ -0,0 +1,93 @@
+// Package logadapters - общие правила вывода ошибок nerr в журналы.
+// Адаптеры для конкретных библиотек находятся во вложенных пакетах и выводят одни и те же ключи
+package logadapters
+
+import (
+	"errors"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Ключи сведений об ошибке
+const (
+	KeyMessage = "message"
+	KeyCode    = "code"
+	KeyOps     = "ops"
+	KeyTrace   = "trace"
+	KeyFields  = "fields"
+	// KeyRequestID - идентификатор запроса (nerr.RequestID), выводится в обоих режимах
+	KeyRequestID = nerr.FieldRequestID
+)
+
+// Mode - режим вывода ошибки в журнал
+type Mode int
+
+const (
+	// Full - текст, код, операции, трасса и поля (по умолчанию)
+	Full Mode = iota
+	// Compact - только внешняя операция и код (nerr.Compact) и код отдельным ключом
+	Compact
+)
+
+// Attr - сведение об ошибке для журнала
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// Attrs возвращает сведения об ошибке в порядке вывода: сообщение, код, идентификатор запроса, операции, трасса и поля
+func Attrs(err error) []Attr {
+	return AttrsMode(err, Full)
+}
+
+// AttrsMode возвращает сведения об ошибке в режиме mode
+func AttrsMode(err error, mode Mode) []Attr {
+	if err == nil {
+		return nil
+	}
+
+	if mode == Compact {
+		attrs := []Attr{{Key: KeyMessage, Value: nerr.Compact(err)}}
+		if code := nerr.OutermostCode(err); code != 0 {
+			attrs = append(attrs, Attr{Key: KeyCode, Value: code})
+		}
+		if id := nerr.RequestID(err); len(id) > 0 {
+			attrs = append(attrs, Attr{Key: KeyRequestID, Value: id})
+		}
+		return attrs
+	}
+
+	attrs := []Attr{{Key: KeyMessage, Value: err.Error()}}
+
+	var e *nerr.Error
+	if !errors.As(err, &e) {
+		return attrs
+	}
+
+	if code := nerr.OutermostCode(e); code != 0 {
+		attrs = append(attrs, Attr{Key: KeyCode, Value: code})
+	}
+	if id := nerr.RequestID(e); len(id) > 0 {
+		attrs = append(attrs, Attr{Key: KeyRequestID, Value: id})
+	}
+
+	var ops []string
+	for _, op := range nerr.Ops(e) {
+		if len(op) > 0 {
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) > 0 {
+		attrs = append(attrs, Attr{Key: KeyOps, Value: ops})
+	}
+
+	if trace := nerr.Trace(e); len(trace) > 0 {
+		attrs = append(attrs, Attr{Key: KeyTrace, Value: trace})
+	}
+
+	if fields := nerr.AllFields(e); len(fields) > 0 {
+		attrs = append(attrs, Attr{Key: KeyFields, Value: fields})
+	}
+
+	return attrs
+}
@@ -0,0 +1,15 @@
+module github.com/n-r-w/nerr/logadapters/logruserr
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/sirupsen/logrus v1.9.0
+)
+
+require (
+	github.com/n-r-w/eno v1.0.1 // indirect
+	golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+)
+
+replace github.com/n-r-w/nerr => ../..
@@ -0,0 +1,28 @@
+// Package logruserr - вывод ошибок nerr в github.com/sirupsen/logrus
+package logruserr
+
+import (
+	"github.com/n-r-w/nerr/logadapters"
+	"github.com/sirupsen/logrus"
+)
+
+// Fields возвращает поля logrus со сведениями об ошибке: текст под logrus.ErrorKey,
+// остальное под ключами с префиксом "error.":
+//
+//	logger.WithFields(logruserr.Fields(err)).Error("request failed")
+func Fields(err error) logrus.Fields {
+	return FieldsMode(err, logadapters.Full)
+}
+
+// FieldsMode возвращает поля logrus со сведениями об ошибке в режиме mode
+func FieldsMode(err error, mode logadapters.Mode) logrus.Fields {
+	res := logrus.Fields{}
+	for _, a := range logadapters.AttrsMode(err, mode) {
+		if a.Key == logadapters.KeyMessage {
+			res[logrus.ErrorKey] = a.Value
+			continue
+		}
+		res[logrus.ErrorKey+"."+a.Key] = a.Value
+	}
+	return res
+}
@@ -0,0 +1,10 @@
+module github.com/n-r-w/nerr/logadapters/slogerr
+
+// log/slog появился в Go 1.21, остальные модули nerr требуют Go 1.19
+go 1.21
+
+require github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ../..
@@ -0,0 +1,100 @@
+// Package slogerr - вывод ошибок nerr в log/slog
+package slogerr
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/logadapters"
+)
+
+// Error возвращает группу "error" со сведениями об ошибке
+func Error(err error) slog.Attr {
+	return NamedError("error", err)
+}
+
+// NamedError возвращает группу key со сведениями об ошибке
+func NamedError(key string, err error) slog.Attr {
+	return NamedErrorMode(key, err, logadapters.Full)
+}
+
+// NamedErrorMode возвращает группу key со сведениями об ошибке в режиме mode
+func NamedErrorMode(key string, err error, mode logadapters.Mode) slog.Attr {
+	attrs := logadapters.AttrsMode(err, mode)
+
+	args := make([]any, 0, len(attrs))
+	for _, a := range attrs {
+		args = append(args, slog.Any(a.Key, a.Value))
+	}
+	return slog.Group(key, args...)
+}
+
+// Handler - обработчик slog, который раскрывает ошибки nerr среди атрибутов записи в группы со сведениями об ошибке
+// (код, операции, трасса и т.д.) и передает запись следующему обработчику. Позволяет получить структурированные
+// ошибки без изменения вызовов журнала:
+//
+//	slog.SetDefault(slog.New(slogerr.NewHandler(slog.NewJSONHandler(os.Stderr, nil))))
+//	slog.Error("request failed", "err", err)
+type Handler struct {
+	next slog.Handler
+	mode logadapters.Mode
+}
+
+// NewHandler возвращает обработчик, раскрывающий ошибки nerr в режиме logadapters.Full
+func NewHandler(next slog.Handler) *Handler {
+	return NewHandlerMode(next, logadapters.Full)
+}
+
+// NewHandlerMode возвращает обработчик, раскрывающий ошибки nerr в режиме mode
+func NewHandlerMode(next slog.Handler, mode logadapters.Mode) *Handler {
+	return &Handler{next: next, mode: mode}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	res := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		res.AddAttrs(h.expand(a))
+		return true
+	})
+	return h.next.Handle(ctx, res)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		expanded = append(expanded, h.expand(a))
+	}
+	return &Handler{next: h.next.WithAttrs(expanded), mode: h.mode}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), mode: h.mode}
+}
+
+// expand заменяет ошибку nerr группой со сведениями о ней, в том числе внутри групп. Остальные атрибуты не меняются
+func (h *Handler) expand(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			attrs = append(attrs, h.expand(ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		var e *nerr.Error
+		if err, ok := v.Any().(error); ok && errors.As(err, &e) {
+			return NamedErrorMode(a.Key, err, h.mode)
+		}
+	}
+
+	return a
+}
@@ -0,0 +1,16 @@
+module github.com/n-r-w/nerr/logadapters/zaperr
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	go.uber.org/zap v1.24.0
+)
+
+require (
+	github.com/n-r-w/eno v1.0.1 // indirect
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+)
+
+replace github.com/n-r-w/nerr => ../..
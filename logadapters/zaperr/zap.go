@@ -0,0 +1,47 @@
+// Package zaperr - вывод ошибок nerr в go.uber.org/zap
+package zaperr
+
+import (
+	"github.com/n-r-w/nerr/logadapters"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Error возвращает поле "error" со сведениями об ошибке
+func Error(err error) zap.Field {
+	return NamedError("error", err)
+}
+
+// NamedError возвращает поле key со сведениями об ошибке
+func NamedError(key string, err error) zap.Field {
+	return NamedErrorMode(key, err, logadapters.Full)
+}
+
+// NamedErrorMode возвращает поле key со сведениями об ошибке в режиме mode
+func NamedErrorMode(key string, err error, mode logadapters.Mode) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.Object(key, object{err: err, mode: mode})
+}
+
+type object struct {
+	err  error
+	mode logadapters.Mode
+}
+
+func (o object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range logadapters.AttrsMode(o.err, o.mode) {
+		switch v := a.Value.(type) {
+		case string:
+			enc.AddString(a.Key, v)
+		case int:
+			enc.AddInt(a.Key, v)
+		default:
+			if err := enc.AddReflected(a.Key, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+module github.com/n-r-w/nerr/logadapters/zerologerr
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/rs/zerolog v1.29.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.12 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	golang.org/x/sys v0.0.0-20210927094055-39ccf1dd6fa6 // indirect
+)
+
+replace github.com/n-r-w/nerr => ../..
@@ -0,0 +1,23 @@
+// Package zerologerr - вывод ошибок nerr в github.com/rs/zerolog
+package zerologerr
+
+import (
+	"github.com/n-r-w/nerr/logadapters"
+	"github.com/rs/zerolog"
+)
+
+// Dict возвращает словарь со сведениями об ошибке:
+//
+//	logger.Error().Dict("error", zerologerr.Dict(err)).Msg("request failed")
+func Dict(err error) *zerolog.Event {
+	return DictMode(err, logadapters.Full)
+}
+
+// DictMode возвращает словарь со сведениями об ошибке в режиме mode
+func DictMode(err error, mode logadapters.Mode) *zerolog.Event {
+	d := zerolog.Dict()
+	for _, a := range logadapters.AttrsMode(err, mode) {
+		d = d.Interface(a.Key, a.Value)
+	}
+	return d
+}
@@ -0,0 +1,76 @@
+package nerr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Logfmt возвращает ошибку в формате logfmt: op=... code=... request_id=... source=... cause=...
+// op - операции всех уровней цепочки, source - место возникновения, cause - текст исходной сторонней ошибки.
+// Пустые значения не выводятся
+func Logfmt(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		ops    []string
+		source string
+		cause  string
+	)
+
+	for cur := err; cur != nil; {
+		e, ok := cur.(*Error)
+		if !ok {
+			cause = messageText(cur.Error())
+			break
+		}
+
+		if op := e.Operation(); len(op) > 0 {
+			ops = append(ops, SanitizeString(op))
+		}
+		if len(e.Place) > 0 {
+			source = e.Place
+		}
+		cur = e.Err
+	}
+
+	var b strings.Builder
+	writeLogfmt(&b, "op", strings.Join(ops, " => "))
+	if code := OutermostCode(err); code != 0 {
+		writeLogfmt(&b, "code", strconv.Itoa(code))
+	}
+	writeLogfmt(&b, FieldRequestID, RequestID(err))
+	writeLogfmt(&b, "source", source)
+	writeLogfmt(&b, "cause", cause)
+
+	return b.String()
+}
+
+func writeLogfmt(b *strings.Builder, key, value string) {
+	if len(value) == 0 {
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+
+	if logfmtNeedsQuote(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func logfmtNeedsQuote(s string) bool {
+	for _, r := range s {
+		if r == '"' || r == '=' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
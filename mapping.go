@@ -0,0 +1,43 @@
+package nerr
+
+import (
+	"context"
+	"errors"
+)
+
+type errorMapping struct {
+	match func(err error) bool
+	code  int
+}
+
+var errorMappings registry[errorMapping]
+
+func init() {
+	MapError(func(err error) bool { return errors.Is(err, context.DeadlineExceeded) }, ErrTimeout)
+	MapError(func(err error) bool { return errors.Is(err, context.Canceled) }, ErrCanceled)
+}
+
+// MapError регистрирует правило: если New создает ошибку без кода, а вложенная ошибка удовлетворяет match,
+// ошибке присваивается code. Правила проверяются в порядке регистрации. Вызывать при инициализации
+func MapError(match func(err error) bool, code int) {
+	errorMappings.add(errorMapping{match: match, code: code})
+}
+
+// ResetErrorMappings удаляет все правила MapError, включая встроенные
+func ResetErrorMappings() {
+	errorMappings.reset()
+}
+
+// InferCode возвращает код по правилам MapError или 0
+func InferCode(err error) int {
+	if err == nil || errorMappings.empty() {
+		return 0
+	}
+
+	for _, m := range errorMappings.items() {
+		if m.match(err) {
+			return m.code
+		}
+	}
+	return 0
+}
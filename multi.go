@@ -0,0 +1,186 @@
+package nerr
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MultiError - набор независимых ошибок. Каждая из них сохраняет свой код и трассу
+type MultiError struct {
+	Errors []error
+	// Dropped - количество ошибок, отброшенных сверх ограничения Collector. Выводится отметкой "… N more"
+	Dropped int
+	// Groups - распределение ошибок Collector по этапам и обработчикам (Collector.Groups). Заполняется, если хотя бы
+	// одна ошибка добавлена с WithWorker, и выводится в Error() после ошибок: "groups: stage=resize worker=7: 12 (40%)"
+	Groups []WorkerGroup
+}
+
+func (m *MultiError) Error() string {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	for i, err := range m.Errors {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		if e, ok := err.(*Error); ok {
+			writeCause(b, e)
+		} else {
+			b.WriteString(err.Error())
+		}
+	}
+	if m.Dropped > 0 {
+		if len(m.Errors) > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(droppedMark(m.Dropped))
+	}
+	if len(m.Groups) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(groupsMark(m.Groups))
+	}
+	return b.String()
+}
+
+func droppedMark(n int) string {
+	return "… " + strconv.Itoa(n) + " more"
+}
+
+func groupsMark(groups []WorkerGroup) string {
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = g.String()
+	}
+	return "groups: " + strings.Join(parts, ", ")
+}
+
+// Unwrap возвращает вложенные ошибки для errors.Is и errors.As
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
+
+// Collector накапливает ошибки, например в цикле или при параллельной обработке. Безопасен для конкурентного использования
+type Collector struct {
+	mu      sync.Mutex
+	errs    []error
+	limit   int
+	dropped int
+	// groups - количество ошибок по этапам и обработчикам (WithWorker), включая отброшенные
+	groups map[workerKey]int
+}
+
+// SetLimit ограничивает количество сохраняемых ошибок: сохраняются первые n, остальные только подсчитываются
+// (MultiError.Dropped). Ограничивает память при обработке больших пакетов. n <= 0 снимает ограничение
+func (c *Collector) SetLimit(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.limit = n
+}
+
+// Add добавляет ошибку. nil игнорируется
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	stage, worker, _ := Worker(err)
+
+	c.mu.Lock()
+	if c.limit > 0 && len(c.errs) >= c.limit {
+		c.dropped++
+	} else {
+		c.errs = append(c.errs, err)
+	}
+	if c.groups == nil {
+		c.groups = make(map[workerKey]int)
+	}
+	c.groups[workerKey{stage: stage, worker: worker}]++
+	c.mu.Unlock()
+}
+
+// Groups возвращает количество и долю добавленных ошибок, включая отброшенные сверх ограничения, по этапам
+// и обработчикам конвейера (WithWorker), по убыванию количества
+func (c *Collector) Groups() []WorkerGroup {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return workerGroups(c.groups)
+}
+
+// Len возвращает количество добавленных ошибок, включая отброшенные сверх ограничения
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.errs) + c.dropped
+}
+
+// Err возвращает nil, если ошибок не было, единственную ошибку или *MultiError. Если ошибки добавлены с WithWorker,
+// MultiError содержит их распределение по этапам и обработчикам (MultiError.Groups)
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case len(c.errs) == 0:
+		return nil
+	case len(c.errs) == 1 && c.dropped == 0:
+		return c.errs[0]
+	default:
+		errs := make([]error, len(c.errs))
+		copy(errs, c.errs)
+		m := &MultiError{Errors: errs, Dropped: c.dropped}
+		if attributed(c.groups) {
+			m.Groups = workerGroups(c.groups)
+		}
+		return m
+	}
+}
+
+// fromJoined преобразует результат errors.Join в *MultiError, сохраняя каждую вложенную ошибку.
+// Остальные ошибки с методом Unwrap() []error (fmt.Errorf с несколькими %w, собственные типы) возвращаются
+// без изменений: у них есть свой текст и тип, доступный через errors.As
+func fromJoined(err error) error {
+	if joinErrorType == nil || reflect.TypeOf(err) != joinErrorType {
+		return err
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err
+	}
+
+	var errs []error
+	for _, child := range joined.Unwrap() {
+		if child != nil {
+			errs = append(errs, child)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return err
+	case 1:
+		return errs[0]
+	default:
+		return &MultiError{Errors: errs}
+	}
+}
+
+func multiTrace(m *MultiError) []string {
+	var res []string
+	for i, err := range m.Errors {
+		for _, line := range Trace(err) {
+			res = append(res, "["+strconv.Itoa(i)+"] "+line)
+		}
+	}
+	if m.Dropped > 0 {
+		res = append(res, droppedMark(m.Dropped))
+	}
+	return res
+}
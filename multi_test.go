@@ -0,0 +1,53 @@
+//go:build go1.20
+
+package nerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+type aggregateError struct {
+	errs []error
+}
+
+func (e *aggregateError) Error() string   { return "aggregate" }
+func (e *aggregateError) Unwrap() []error { return e.errs }
+
+func TestNewJoinedCause(t *testing.T) {
+	e1 := errors.New("e1")
+	e2 := errors.New("e2")
+
+	t.Run("errors.Join", func(t *testing.T) {
+		err := nerr.New("op", errors.Join(e1, e2))
+
+		var m *nerr.MultiError
+		if !errors.As(err, &m) || len(m.Errors) != 2 {
+			t.Fatalf("want MultiError with 2 errors, got %#v", err)
+		}
+	})
+
+	t.Run("fmt.Errorf", func(t *testing.T) {
+		err := nerr.New("op", fmt.Errorf("load %s: %w, %w", "42", e1, e2))
+
+		if !strings.Contains(err.Error(), "load 42: e1, e2") {
+			t.Fatalf("wrapper text lost: %q", err.Error())
+		}
+		if !errors.Is(err, e1) || !errors.Is(err, e2) {
+			t.Fatal("wrapped errors lost")
+		}
+	})
+
+	t.Run("custom aggregate", func(t *testing.T) {
+		err := nerr.New("op", &aggregateError{errs: []error{e1, e2}})
+
+		var agg *aggregateError
+		if !errors.As(err, &agg) {
+			t.Fatal("aggregate type lost")
+		}
+	})
+}
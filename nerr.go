@@ -1,14 +1,17 @@
 package nerr
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"runtime"
+	"math"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
-	"github.com/jackc/pgconn"
-	"github.com/lib/pq"
 	"github.com/n-r-w/eno"
 )
 
@@ -17,57 +20,259 @@ type Error struct {
 	Code  int
 	Place string
 	Err   error
+	Stack []Frame
+
+	Goroutine uint64
+	Labels    map[string]string
+
+	Time   time.Time
+	Fields map[string]any
+
+	// значения, прикрепленные WithValue
+	values []any
+
+	// операция NewFmtLazy, форматируемая при первом обращении к Operation
+	lazyOp *lazyMessage
+
+	// кэш текста Error(), *cachedMessage
+	msg atomic.Value
+}
+
+type cachedMessage struct {
+	config *Config
+	text   string
 }
 
+// CaptureTime включает запись времени создания ошибки в Error.Time (см. WithCaptureTime)
+func CaptureTime(enable bool) {
+	Configure(WithCaptureTime(enable))
+}
+
+// Error возвращает текст ошибки. Текст вычисляется один раз и кэшируется до изменения настроек (Configure),
+// поэтому поля созданной ошибки не следует менять напрямую - для этого есть WithCode, WithFields и AddOp
 func (e *Error) Error() string {
-	var res []string
+	c := cfg()
+	if m, ok := e.msg.Load().(*cachedMessage); ok && m.config == c {
+		return m.text
+	}
+
+	var text string
+	if c.Formatter != nil {
+		text = c.Formatter(e)
+	} else {
+		text = FormatDefault(e)
+	}
+
+	e.msg.Store(&cachedMessage{config: c, text: text})
+	return text
+}
 
-	if len(e.Op) > 0 {
-		res = append(res, fmt.Sprintf("op: %s", e.Op))
+// FormatDefault возвращает стандартный текст ошибки, используемый Error(), если не задан Config.Formatter
+func FormatDefault(e *Error) string {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	writeDefault(b, e)
+	return b.String()
+}
+
+// AppendTo добавляет текст Error() к dst и возвращает расширенный срез. Стандартный текст формируется
+// непосредственно в dst, без промежуточных строк
+func (e *Error) AppendTo(dst []byte) []byte {
+	c := cfg()
+	if m, ok := e.msg.Load().(*cachedMessage); ok && m.config == c {
+		return append(dst, m.text...)
+	}
+	if c.Formatter != nil {
+		return append(dst, e.Error()...)
 	}
 
-	code := e.TopCode()
-	if code > 0 {
-		res = append(res, fmt.Sprintf("code: %d", code))
+	b := bytes.NewBuffer(dst)
+	writeDefault(b, e)
+	return b.Bytes()
+}
+
+// writeDefault выводит стандартный текст ошибки за один проход, включая тексты вложенных уровней
+func writeDefault(b *bytes.Buffer, e *Error) {
+	limit := cfg().MaxChainDepth
+	if limit > 0 && chainLenMax(e, limit+1) > limit {
+		writeLimited(b, e, limit)
+		return
 	}
 
-	if trace := e.Trace(); len(trace) > 0 {
-		res = append(res, fmt.Sprintf("source: %s", trace[len(trace)-1]))
+	start := b.Len()
+	last, repeats := opRun(e)
+	writeHead(b, e, repeats)
+
+	if b.Len() == start && last.Err == nil {
+		b.WriteString("undefined")
+		return
 	}
 
-	if len(res) == 0 && e.Err == nil {
-		return "undefined"
+	if last.Err != nil {
+		if b.Len() > start {
+			b.WriteString(" => ")
+		}
+		writeCause(b, last.Err)
 	}
+}
 
-	s := strings.Join(res, ", ")
+// writeCause выводит текст вложенной ошибки. Для *Error без Config.Formatter текст формируется в том же буфере
+func writeCause(b *bytes.Buffer, err error) {
+	if e, ok := err.(*Error); ok && cfg().Formatter == nil {
+		if m, ok := e.msg.Load().(*cachedMessage); ok && m.config == cfg() {
+			b.WriteString(m.text)
+			return
+		}
+		writeDefault(b, e)
+		return
+	}
 
-	if e.Err != nil {
-		if len(s) == 0 {
-			s = e.Err.Error()
+	b.WriteString(causeText(err))
+}
+
+// opRun возвращает последний из идущих подряд уровней с той же операцией, что у e, и их количество.
+// Такие уровни (например, при повторах) выводятся одним уровнем "op (xN)"
+func opRun(e *Error) (*Error, int) {
+	op := e.Operation()
+	if len(op) == 0 {
+		return e, 1
+	}
+
+	n := 1
+	for {
+		next, ok := e.Err.(*Error)
+		if !ok || next.Operation() != op {
+			return e, n
+		}
+		e = next
+		n++
+	}
+}
+
+// writeLimited выводит не более limit уровней цепочки и отметку о количестве остальных
+func writeLimited(b *bytes.Buffer, e *Error, limit int) {
+	for i := 1; ; i++ {
+		writeHead(b, e, 1)
+		if e.Err == nil {
+			break
+		}
+
+		b.WriteString(" => ")
+		next, ok := e.Err.(*Error)
+		if !ok {
+			b.WriteString(causeText(e.Err))
+			break
+		}
+		if i == limit {
+			b.WriteString(moreLevels(next))
+			break
+		}
+		e = next
+	}
+}
+
+// writeHead выводит операцию, код и место возникновения уровня. repeats > 1 - количество подряд идущих уровней
+// с той же операцией
+func writeHead(b *bytes.Buffer, e *Error, repeats int) {
+	start := b.Len()
+
+	if op := e.Operation(); len(op) > 0 {
+		b.WriteString("op: ")
+		b.WriteString(SanitizeString(op))
+		if repeats > 1 {
+			b.WriteString(" (x")
+			b.WriteString(strconv.Itoa(repeats))
+			b.WriteString(")")
+		}
+	}
+
+	if code := OutermostCode(e); code > 0 {
+		if b.Len() > start {
+			b.WriteString(", ")
+		}
+		b.WriteString("code: ")
+		b.WriteString(strconv.Itoa(code))
+	}
+
+	if level, line, ok := traceSource(e); ok {
+		if b.Len() > start {
+			b.WriteString(", ")
+		}
+		b.WriteString("source: ")
+		if level != nil {
+			writeTraceLine(b, level)
 		} else {
-			s = fmt.Sprintf("%s => %v", s, e.Err)
+			b.WriteString(line)
 		}
 	}
+}
+
+// moreLevels возвращает отметку о пропущенных уровнях, начиная с e
+func moreLevels(e error) string {
+	return "… " + strconv.Itoa(chainLenMax(e, maxCountedLevels)) + " more"
+}
+
+// предел подсчета пропущенных уровней на случай зацикленной цепочки
+const maxCountedLevels = 1 << 20
+
+// lastTraceLine возвращает последнюю строку Trace без построения всей трассы
+func lastTraceLine(e *Error) (string, bool) {
+	level, line, ok := traceSource(e)
+	if ok && level != nil {
+		return traceLine(level), true
+	}
+	return line, ok
+}
 
-	return s
+// traceSource определяет последнюю строку Trace: уровень, строку трассы которого нужно вывести,
+// или готовую строку, если цепочка заканчивается MultiError
+func traceSource(e *Error) (level *Error, line string, ok bool) {
+	limit := cfg().MaxChainDepth
+	for depth := 1; ; depth++ {
+		switch v := e.Err.(type) {
+		case *Error:
+			if limit <= 0 || depth < limit {
+				e = v
+				continue
+			}
+		case *MultiError:
+			trace := multiTrace(v)
+			if v.Dropped > 0 {
+				// отметка об отброшенных ошибках не является источником
+				trace = trace[:len(trace)-1]
+			}
+			if len(trace) > 0 {
+				return nil, trace[len(trace)-1], true
+			}
+		}
+		// у ошибок без места возникновения (NewLite) источник не выводится
+		return e, "", len(e.Place) > 0
+	}
 }
 
 func (e *Error) Unwrap() error {
-	if e.Err == nil {
-		return e
-	} else {
-		return e.Err
+	return e.Err
+}
+
+// As позволяет использовать в errors.As сопоставители ошибок с методом MatchError(*Error) bool (см. nerrtest)
+func (e *Error) As(target any) bool {
+	if m, ok := target.(interface{ MatchError(e *Error) bool }); ok {
+		return m.MatchError(e)
 	}
+	return false
 }
 
 func (e *Error) Ops() []string {
 	return Ops(e)
 }
 
+// Deprecated: используйте OutermostCode
 func (e *Error) TopCode() int {
-	return TopCode(e)
+	return OutermostCode(e)
 }
 
+// Deprecated: используйте InnermostOp или OutermostOp
 func (e *Error) TopOp() string {
 	return TopOp(e)
 }
@@ -76,11 +281,52 @@ func (e *Error) Trace() []string {
 	return Trace(e)
 }
 
+// New создает ошибку из аргументов: операций (string), кода (int, eno.ErrNo), причины (error, []error, []any),
+// полей (map[string]any), контекста и Skip. Пустые аргументы (nil, "", нулевой код, пустые списки) пропускаются.
+// Возвращается nil, только если аргументы не задают ни причину, ни операцию, ни код, ни поля.
+// Поэтому New("op", err) при err == nil возвращает ошибку с операцией op. Для оборачивания ошибки, которая может
+// быть nil (например в defer), используйте WrapOnce
 func New(args ...any) error {
-	return NewLevel(2, args)
+	e := newError(2, args)
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
+}
+
+// Skip - аргумент New, пропускающий n дополнительных уровней стека при определении места возникновения.
+// Используется во вспомогательных функциях, создающих ошибку от имени вызывающего: New(Skip(1), ...)
+type Skip int
+
+// NewLite аналог New без определения места возникновения, стека и горутины. Предназначен для ожидаемых
+// частых ошибок (проверка данных, не найдено), которые не попадают в журнал как инциденты
+func NewLite(args ...any) error {
+	e := newError(noTrace, args)
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
 }
 
+// Deprecated: используйте New со Skip. NewLevel(n, ...) равнозначен New(Skip(n-1), ...)
 func NewLevel(codeLevel int, args ...any) error {
+	e := newError(codeLevel+1, args)
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
+}
+
+// noTrace - значение codeLevel, при котором newError не определяет место возникновения и горутину
+const noTrace = -1
+
+func newError(codeLevel int, args []any) *Error {
 	if len(args) == 1 {
 		if args[0] == nil {
 			return nil
@@ -91,22 +337,68 @@ func NewLevel(codeLevel int, args ...any) error {
 	}
 
 	e := &Error{}
+	c := cfg()
+
+	if codeLevel != noTrace {
+		codeLevel += skipLevels(args)
+		if stack, full := captureStack(c, codeLevel); len(stack) > 0 {
+			e.Place = stack[0].String()
+			if full {
+				e.Stack = stack
+			}
+		}
+	}
 
-	if pc, file, line, ok := runtime.Caller(codeLevel); ok {
-		details := runtime.FuncForPC(pc)
-		e.Place = fmt.Sprintf("%s (%s:%d)", details.Name(), file, line)
+	if (c.CaptureGoroutine || c.CrossGoroutineStacks) && codeLevel != noTrace {
+		e.Goroutine = goroutineID()
 	}
 
+	if c.CaptureTime {
+		e.Time = time.Now()
+	}
+
+	var meaningful bool
 	for _, arg := range args {
-		if !prepareProperty(e, arg) {
-			return nil
+		if prepareProperty(e, arg) {
+			meaningful = true
 		}
+	}
+	if !meaningful {
+		return nil
+	}
+
+	// окружение одно для всей цепочки, поэтому записывается только на самом вложенном уровне nerr
+	if c.CaptureEnvironment && !errors.As(e.Err, new(*Error)) {
+		for k, v := range environmentFields() {
+			if _, ok := e.Fields[k]; !ok {
+				setField(e, k, v)
+			}
+		}
+	}
 
+	if c.CrossGoroutineStacks && codeLevel != noTrace && len(e.Stack) == 0 && crossesGoroutine(e) {
+		depth := c.StackDepth
+		if depth <= 0 {
+			depth = defaultWithStackDepth
+		}
+		e.Stack = callersDepth(codeLevel, depth)
+	}
+
+	if e.Err != nil {
+		if OutermostCode(e) == 0 {
+			e.Code = InferCode(e.Err)
+		}
+		addExecFields(e)
+		addPathFields(e)
+		addCertificateFields(e)
+		addJSONFields(e)
 	}
 
 	return e
 }
 
+// prepareProperty переносит аргумент в ошибку. Возвращает false для пустых аргументов и аргументов,
+// не задающих содержимое ошибки (контекст, Skip)
 func prepareProperty(e *Error, arg any) bool {
 	if arg == nil {
 		return false
@@ -114,6 +406,10 @@ func prepareProperty(e *Error, arg any) bool {
 
 	switch v := arg.(type) {
 	case string:
+		if len(v) == 0 {
+			return false
+		}
+		e.resolveLazyOp()
 		if len(e.Op) > 0 {
 			if len(v) > 0 && e.Op != v {
 				e.Op += ", " + v
@@ -122,15 +418,21 @@ func prepareProperty(e *Error, arg any) bool {
 			e.Op = v
 		}
 	case eno.ErrNo:
+		if v == 0 {
+			return false
+		}
 		e.Code = int(v)
 		if len(e.Op) == 0 {
 			e.Op = eno.Name(v)
 		}
-	case int, int8, int32:
+	case int:
+		if v == 0 {
+			return false
+		}
 		if e.Code != 0 {
 			panic("code duplication")
 		}
-		e.Code = v.(int)
+		e.Code = v
 	case []error:
 		if len(v) == 1 {
 			return prepareProperty(e, v[0])
@@ -176,106 +478,439 @@ func prepareProperty(e *Error, arg any) bool {
 		} else {
 			return false
 		}
+	case map[string]any:
+		for k, val := range v {
+			setField(e, k, val)
+		}
+		return len(v) > 0
+	case Skip:
+		// учтен в newError
+		return false
+	case *lazyMessage:
+		e.lazyOp = v
+	case context.Context:
+		prepareContext(e, v)
+		return false
 	case error:
+		if isNilError(v) {
+			return false
+		}
 		if e.Err != nil {
 			panic("error duplication")
 		}
-		e.Err = v
+		e.Err = fromJoined(v)
 
 	default:
-		panic(fmt.Sprintf("invalid argument type: %T", arg))
+		code, ok := integerCode(arg)
+		if !ok {
+			panic(fmt.Sprintf("invalid argument type: %T", arg))
+		}
+		if code == 0 {
+			return false
+		}
+		if e.Code != 0 {
+			panic("code duplication")
+		}
+		e.Code = code
+
+		// перечисления, созданные stringer, задают операцию по умолчанию, как eno.ErrNo
+		if s, ok := arg.(fmt.Stringer); ok && len(e.Op) == 0 {
+			e.Op = s.String()
+		}
 	}
 
 	return true
 }
 
-func NewFmt(format string, args ...any) error {
-	return New(fmt.Sprintf(format, args...))
+// isNilError сообщает, что err - nil-указатель, приведенный к error (например (*Error)(nil))
+func isNilError(err error) bool {
+	v := reflect.ValueOf(err)
+	return v.Kind() == reflect.Pointer && v.IsNil()
 }
 
-func Ops(e error) []string {
-	res := []string{}
-	if e == nil {
-		return res
+// skipLevels возвращает сумму аргументов Skip
+func skipLevels(args []any) int {
+	var n int
+	for _, arg := range args {
+		if s, ok := arg.(Skip); ok {
+			n += int(s)
+		}
 	}
+	return n
+}
+
+// integerCode преобразует в код значение любого целочисленного типа, в том числе именованного.
+// Значения, не помещающиеся в int, приводят к панике
+func integerCode(arg any) (int, bool) {
+	v := reflect.ValueOf(arg)
 
-	switch v := e.(type) {
-	case *Error:
-		res = append(res, v.Op)
-		if v.Err != nil {
-			res = append(res, Ops(v.Err)...)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := v.Int()
+		if int64(int(n)) != n {
+			panic(fmt.Sprintf("code overflow: %d", n))
 		}
-		return res
+		return int(n), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := v.Uint()
+		if n > math.MaxInt {
+			panic(fmt.Sprintf("code overflow: %d", n))
+		}
+		return int(n), true
 	default:
-		return []string{v.Error()}
+		return 0, false
 	}
 }
 
-func TopCode(e error) int {
+func NewFmt(format string, args ...any) error {
+	return New(Skip(1), fmt.Sprintf(format, args...))
+}
+
+// ForeignOps - представление сторонней ошибки (не *Error) в результате OpsMode
+type ForeignOps int
+
+const (
+	// ForeignMessage - текст ошибки (как в Ops)
+	ForeignMessage ForeignOps = iota
+	// ForeignOmit - сторонние ошибки пропускаются
+	ForeignOmit
+	// ForeignType - имя типа ошибки, например "*fs.PathError". Подходит для меток метрик
+	ForeignType
+)
+
+func Ops(e error) []string {
+	return OpsMode(e, ForeignMessage)
+}
+
+// OpsMode возвращает операции всех уровней цепочки, представляя сторонние ошибки согласно foreign
+func OpsMode(e error, foreign ForeignOps) []string {
 	if e == nil {
-		return 0
+		return []string{}
 	}
 
-	switch v := e.(type) {
-	case *Error:
-		if v.Code != 0 {
-			return int(v.Code)
+	return appendOps(make([]string, 0, chainLen(e)+1), e, foreign)
+}
+
+func appendOps(res []string, e error, foreign ForeignOps) []string {
+	for e != nil {
+		switch v := e.(type) {
+		case *Error:
+			res = append(res, SanitizeString(v.Operation()))
+			e = v.Err
+		case *MultiError:
+			for _, err := range v.Errors {
+				res = appendOps(res, err, foreign)
+			}
+			return res
+		default:
+			switch foreign {
+			case ForeignOmit:
+				return res
+			case ForeignType:
+				return append(res, fmt.Sprintf("%T", v))
+			default:
+				return append(res, messageText(v.Error()))
+			}
 		}
-		return TopCode(v.Err)
-	default:
-		return 0
 	}
+	return res
 }
 
+// chainLen возвращает количество уровней *Error до первой сторонней ошибки или MultiError,
+// но не больше Config.MaxChainDepth
+func chainLen(e error) int {
+	return chainLenMax(e, cfg().MaxChainDepth)
+}
+
+// chainLenMax аналог chainLen с ограничением max. При max <= 0 - без ограничения
+func chainLenMax(e error, max int) int {
+	n := 0
+	for max <= 0 || n < max {
+		v, ok := e.(*Error)
+		if !ok || v == nil {
+			return n
+		}
+		n++
+		e = v.Err
+	}
+	return n
+}
+
+// TopCode возвращает код ближайшего к внешнему уровня цепочки.
+//
+// Deprecated: используйте OutermostCode
+func TopCode(e error) int {
+	return OutermostCode(e)
+}
+
+// TopOp возвращает последний элемент Ops: операцию самого вложенного уровня или текст сторонней ошибки,
+// а при отсутствии операций - код строкой.
+//
+// Deprecated: используйте InnermostOp или OutermostOp
 func TopOp(e error) string {
 	ops := Ops(e)
 	if len(ops) > 0 {
 		return ops[len(ops)-1]
 	} else {
-		return strconv.Itoa(TopCode(e))
+		return strconv.Itoa(OutermostCode(e))
+	}
+}
+
+// OutermostCode возвращает первый ненулевой код, начиная с внешнего уровня цепочки. Для MultiError - код
+// первой вложенной ошибки с кодом
+func OutermostCode(e error) int {
+	for e != nil {
+		switch v := e.(type) {
+		case *Error:
+			if v.Code != 0 {
+				return v.Code
+			}
+			e = v.Err
+		case *MultiError:
+			for _, err := range v.Errors {
+				if code := OutermostCode(err); code != 0 {
+					return code
+				}
+			}
+			return 0
+		default:
+			return foreignCode(v)
+		}
+	}
+	return 0
+}
+
+// InnermostCode возвращает последний ненулевой код цепочки, то есть код самого вложенного уровня, где он задан.
+// Для MultiError - код первой вложенной ошибки с кодом
+func InnermostCode(e error) int {
+	res := 0
+	for e != nil {
+		switch v := e.(type) {
+		case *Error:
+			if v.Code != 0 {
+				res = v.Code
+			}
+			e = v.Err
+		case *MultiError:
+			for _, err := range v.Errors {
+				if code := InnermostCode(err); code != 0 {
+					return code
+				}
+			}
+			return res
+		default:
+			if code := foreignCode(v); code != 0 {
+				return code
+			}
+			return res
+		}
+	}
+	return res
+}
+
+// OutermostOp возвращает первую непустую операцию, начиная с внешнего уровня цепочки, или пустую строку.
+// Текст сторонних ошибок операцией не считается
+func OutermostOp(e error) string {
+	for _, op := range OpsMode(e, ForeignOmit) {
+		if len(op) > 0 {
+			return op
+		}
+	}
+	return ""
+}
+
+// InnermostOp возвращает операцию самого вложенного уровня цепочки, где она задана, или пустую строку.
+// Текст сторонних ошибок операцией не считается
+func InnermostOp(e error) string {
+	ops := OpsMode(e, ForeignOmit)
+	for i := len(ops) - 1; i >= 0; i-- {
+		if len(ops[i]) > 0 {
+			return ops[i]
+		}
 	}
+	return ""
 }
 
 func Trace(e error) []string {
-	res := []string{}
 	if e == nil {
-		return res
+		return []string{}
 	}
 
-	switch v := e.(type) {
-	case *Error:
-		info := []string{v.Place}
-		if len(v.Op) > 0 {
-			info = append(info, "op: "+v.Op)
+	return appendTrace(make([]string, 0, chainLen(e)), e)
+}
+
+// AppendTrace добавляет к dst строки Trace, разделенные переводом строки, и возвращает расширенный срез
+func AppendTrace(dst []byte, e error) []byte {
+	b := bytes.NewBuffer(dst)
+	start := b.Len()
+	newLine := func() {
+		if b.Len() > start {
+			b.WriteByte('\n')
 		}
-		if v.Code != 0 {
-			info = append(info, fmt.Sprintf("code: %d", v.Code))
+	}
+
+	limit := cfg().MaxChainDepth
+	for depth := 0; e != nil; depth++ {
+		switch v := e.(type) {
+		case *Error:
+			newLine()
+			if limit > 0 && depth == limit {
+				b.WriteString(moreLevels(v))
+				return b.Bytes()
+			}
+			writeTraceLine(b, v)
+			e = v.Err
+		case *MultiError:
+			for _, line := range multiTrace(v) {
+				newLine()
+				b.WriteString(line)
+			}
+			return b.Bytes()
+		default:
+			return b.Bytes()
 		}
+	}
+	return b.Bytes()
+}
 
-		res = append(res, strings.Join(info, "; "))
-		if v.Err != nil {
-			res = append(res, Trace(v.Err)...)
+func appendTrace(res []string, e error) []string {
+	limit := cfg().MaxChainDepth
+	for depth := 0; e != nil; depth++ {
+		switch v := e.(type) {
+		case *Error:
+			if limit > 0 && depth == limit {
+				return append(res, moreLevels(v))
+			}
+			res = append(res, traceLine(v))
+			e = v.Err
+		case *MultiError:
+			return append(res, multiTrace(v)...)
+		default:
+			return res
 		}
-		return res
-	default:
-		return res
+	}
+	return res
+}
+
+func traceLine(v *Error) string {
+	b := getBuffer()
+	defer putBuffer(b)
+
+	writeTraceLine(b, v)
+	return b.String()
+}
+
+func writeTraceLine(b *bytes.Buffer, v *Error) {
+	// уровни из другого компонента отмечаются в начале строки: "[remote service] place; ..."
+	if mark, ok := remoteMark(v); ok {
+		b.WriteString("[" + mark + "] ")
+	}
+	b.WriteString(v.Place)
+
+	if op := v.Operation(); len(op) > 0 {
+		b.WriteString("; op: ")
+		b.WriteString(SanitizeString(op))
+	}
+	if v.Code != 0 {
+		b.WriteString("; code: ")
+		b.WriteString(strconv.Itoa(v.Code))
+	}
+	if !v.Time.IsZero() {
+		b.WriteString("; time: ")
+		var buf [64]byte
+		b.Write(v.Time.AppendFormat(buf[:0], time.RFC3339Nano))
+	}
+	if v.Goroutine != 0 {
+		b.WriteString("; goroutine: ")
+		var buf [20]byte
+		b.Write(strconv.AppendUint(buf[:0], v.Goroutine, 10))
+	}
+	if len(v.Labels) > 0 {
+		b.WriteString("; labels: ")
+		writeLabels(b, v.Labels)
+	}
+	if len(v.Fields) > 0 {
+		b.WriteString("; fields: ")
+		writeFields(b, v.Fields)
 	}
 }
 
 func IsCode(err error, code int) bool {
-	if err == nil {
-		return false
+	for err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if code != 0 && v.Code == code {
+				return true
+			}
+			if code == 0 && OutermostCode(v) == 0 {
+				return true
+			}
+			err = v.Err
+		case *MultiError:
+			if code == 0 && OutermostCode(v) == 0 {
+				return true
+			}
+			for _, e := range v.Errors {
+				if IsCode(e, code) {
+					return true
+				}
+			}
+			return false
+		default:
+			return foreignCode(v) == code
+		}
 	}
 
-	c := TopCode(err)
-	if c == code {
-		return true
+	return false
+}
+
+// HasAnyCode проверяет, есть ли в цепочке ошибка с одним из кодов codes. Нулевой код не учитывается.
+// Как и IsCode, не выделяет память
+func HasAnyCode(err error, codes ...int) bool {
+	for err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if v.Code != 0 && containsCode(codes, v.Code) {
+				return true
+			}
+			err = v.Err
+		case *MultiError:
+			for _, e := range v.Errors {
+				if HasAnyCode(e, codes...) {
+					return true
+				}
+			}
+			return false
+		default:
+			if code := foreignCode(v); code != 0 {
+				return containsCode(codes, code)
+			}
+			return false
+		}
 	}
 
-	if e, ok := err.(*Error); ok && e.Err != nil {
-		return IsCode(e.Err, code)
+	return false
+}
+
+// foreignCode возвращает код сторонней ошибки с методом Code() int, если включен Config.ForeignCodes
+func foreignCode(err error) int {
+	if !cfg().ForeignCodes {
+		return 0
+	}
+
+	var coder interface{ Code() int }
+	if errors.As(err, &coder) {
+		return coder.Code()
 	}
+	return 0
+}
 
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
 	return false
 }
 
@@ -291,16 +926,43 @@ func Unwrap(err error) error {
 	return errors.Unwrap(err)
 }
 
-// Сравнение кодов с помощью github.com/jackc/pgerrcode
-func SqlCode(err error) string {
-	e := Unwrap(err)
+// AsError возвращает первую *Error в цепочке err, в том числе обернутую сторонними ошибками (fmt.Errorf с %w)
+// или вложенную в MultiError и errors.Join
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
 
-	if pqerr, ok := e.(*pq.Error); ok {
-		return string(pqerr.Code)
+// AllNerr возвращает все *Error дерева ошибок err в порядке обхода errors.As: сначала уровень, затем вложенные
+// ошибки, для Unwrap() []error - каждая ветвь по очереди
+func AllNerr(err error) []*Error {
+	var res []*Error
+	walkErrors(err, func(err error) {
+		if e, ok := err.(*Error); ok {
+			res = append(res, e)
+		}
+	})
+	return res
+}
 
-	} else if pqerr, ok := e.(*pgconn.PgError); ok {
-		return pqerr.Code
+// walkErrors вызывает fn для каждой ошибки дерева err в порядке обхода errors.As
+func walkErrors(err error, fn func(err error)) {
+	for err != nil {
+		fn(err)
+
+		switch v := err.(type) {
+		case interface{ Unwrap() error }:
+			err = v.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, child := range v.Unwrap() {
+				walkErrors(child, fn)
+			}
+			return
+		default:
+			return
+		}
 	}
-
-	return ""
 }
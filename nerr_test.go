@@ -0,0 +1,179 @@
+package nerr_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+// глубины цепочек для бенчмарков
+var benchDepths = []int{1, 8, 32}
+
+// deepChain возвращает цепочку из depth уровней *nerr.Error над сторонней ошибкой
+func deepChain(depth int) *nerr.Error {
+	var err error = errors.New("connection refused")
+	for i := 0; i < depth; i++ {
+		if i == depth/2 {
+			err = nerr.New("level"+strconv.Itoa(i), nerr.ErrUnavailable, map[string]any{"attempt": i}, err)
+		} else {
+			err = nerr.New("level"+strconv.Itoa(i), err)
+		}
+	}
+	return err.(*nerr.Error)
+}
+
+func TestFormatDefault(t *testing.T) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+		text := nerr.FormatDefault(e)
+
+		if want := e.Error(); text != want {
+			t.Fatalf("depth %d: FormatDefault() = %q, Error() = %q", depth, text, want)
+		}
+		for _, part := range []string{"op: level0", "op: level" + strconv.Itoa(depth-1), "connection refused"} {
+			if !strings.Contains(text, part) {
+				t.Fatalf("depth %d: %q not found in %q", depth, part, text)
+			}
+		}
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+
+		b.Run("cached/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = e.Error()
+			}
+		})
+
+		// FormatDefault не использует кэш Error() и каждый раз формирует текст заново
+		b.Run("format/depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = nerr.FormatDefault(e)
+			}
+		})
+	}
+}
+
+func TestHasAnyCodeAllocs(t *testing.T) {
+	multi := &nerr.MultiError{Errors: []error{errors.New("other"), deepChain(8)}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"foreign", errors.New("plain"), false},
+		{"deep found", deepChain(32), true},
+		{"deep missing", nerr.New("op", errors.New("plain")), false},
+		{"multi", multi, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nerr.HasAnyCode(tt.err, nerr.ErrNotFound, nerr.ErrUnavailable); got != tt.want {
+				t.Fatalf("HasAnyCode() = %v, want %v", got, tt.want)
+			}
+
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = nerr.HasAnyCode(tt.err, nerr.ErrNotFound, nerr.ErrUnavailable)
+			})
+			if allocs != 0 {
+				t.Fatalf("HasAnyCode allocates: %v allocs/op", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkHasAnyCode(b *testing.B) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = nerr.HasAnyCode(e, nerr.ErrNotFound, nerr.ErrUnavailable)
+			}
+		})
+	}
+}
+
+func TestAppendTo(t *testing.T) {
+	for _, depth := range benchDepths {
+		// до вызова Error(): текст формируется непосредственно в dst
+		e := deepChain(depth)
+		got := string(e.AppendTo([]byte("prefix ")))
+
+		if want := "prefix " + e.Error(); got != want {
+			t.Fatalf("depth %d: AppendTo() = %q, want %q", depth, got, want)
+		}
+		// после Error(): добавляется кэшированный текст
+		if got := string(e.AppendTo(nil)); got != e.Error() {
+			t.Fatalf("depth %d: cached AppendTo() = %q, want %q", depth, got, e.Error())
+		}
+	}
+}
+
+func TestAppendTrace(t *testing.T) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+		got := string(nerr.AppendTrace([]byte("trace:\n"), e))
+
+		if want := "trace:\n" + strings.Join(nerr.Trace(e), "\n"); got != want {
+			t.Fatalf("depth %d: AppendTrace() = %q, want %q", depth, got, want)
+		}
+	}
+
+	if got := nerr.AppendTrace(nil, nil); len(got) != 0 {
+		t.Fatalf("AppendTrace(nil) = %q", got)
+	}
+}
+
+func BenchmarkAppendTo(b *testing.B) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+		buf := make([]byte, 0, 4096)
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = e.AppendTo(buf[:0])
+			}
+		})
+	}
+}
+
+func BenchmarkAppendTrace(b *testing.B) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+		buf := make([]byte, 0, 16<<10)
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = nerr.AppendTrace(buf[:0], e)
+			}
+		})
+	}
+}
+
+func BenchmarkTrace(b *testing.B) {
+	for _, depth := range benchDepths {
+		e := deepChain(depth)
+
+		b.Run("depth="+strconv.Itoa(depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = nerr.Trace(e)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package nerraws - классификация ошибок AWS SDK v2.
+// Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable для ошибок AWS
+package nerraws
+
+import (
+	"errors"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/n-r-w/nerr"
+)
+
+// Поля, которые Wrap добавляет к ошибке
+const (
+	FieldErrorCode  = "aws.error_code"
+	FieldRequestID  = "aws.request_id"
+	FieldHTTPStatus = "aws.http_status"
+)
+
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+var timeoutCodes = map[string]bool{
+	"RequestTimeout":          true,
+	"RequestTimeoutException": true,
+}
+
+// Коды ошибок S3 и других хранилищ объектов AWS
+var (
+	notFoundCodes = map[string]bool{
+		"NoSuchKey":      true,
+		"NoSuchBucket":   true,
+		"NoSuchUpload":   true,
+		"NoSuchVersion":  true,
+		"NotFound":       true,
+		"ObjectNotFound": true,
+	}
+
+	preconditionCodes = map[string]bool{
+		"PreconditionFailed":         true,
+		"ConditionalRequestConflict": true,
+	}
+)
+
+func init() {
+	nerr.MapError(IsNotFound, nerr.ErrNotFound)
+	nerr.MapError(IsPreconditionFailed, nerr.ErrConflict)
+	nerr.MapError(IsThrottling, nerr.ErrRateLimited)
+	nerr.MapError(IsTimeout, nerr.ErrTimeout)
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// Wrap оборачивает ошибку AWS в *nerr.Error с полями кода ошибки, идентификатора запроса и HTTP статуса
+func Wrap(err error) error {
+	return nerr.New(nerr.Skip(1), err, Fields(err))
+}
+
+// Fields возвращает код ошибки, идентификатор запроса и HTTP статус из любого места цепочки
+func Fields(err error) map[string]any {
+	res := make(map[string]any)
+
+	if code := ErrorCode(err); len(code) > 0 {
+		res[FieldErrorCode] = code
+	}
+	if id := RequestID(err); len(id) > 0 {
+		res[FieldRequestID] = id
+	}
+	if status := HTTPStatus(err); status != 0 {
+		res[FieldHTTPStatus] = status
+	}
+
+	return res
+}
+
+// ErrorCode возвращает код ошибки API AWS
+func ErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+// RequestID возвращает идентификатор запроса AWS
+func RequestID(err error) string {
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.ServiceRequestID()
+	}
+	return ""
+}
+
+// HTTPStatus возвращает HTTP статус ответа AWS или 0
+func HTTPStatus(err error) int {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+	return 0
+}
+
+// IsNotFound проверяет отсутствие объекта или бакета. Для HEAD запросов S3 возвращает код NotFound
+func IsNotFound(err error) bool {
+	return notFoundCodes[ErrorCode(err)]
+}
+
+// IsPreconditionFailed проверяет невыполнение условия запроса (If-Match и т.п., статус 412)
+func IsPreconditionFailed(err error) bool {
+	return preconditionCodes[ErrorCode(err)] || HTTPStatus(err) == http.StatusPreconditionFailed
+}
+
+// IsThrottling проверяет, что запрос отклонен из-за превышения лимитов
+func IsThrottling(err error) bool {
+	return throttlingCodes[ErrorCode(err)]
+}
+
+// IsTimeout проверяет истечение времени ожидания запроса
+func IsTimeout(err error) bool {
+	return timeoutCodes[ErrorCode(err)]
+}
+
+// IsRetryable проверяет, что запрос можно повторить: превышение лимитов, таймаут или ошибка на стороне сервиса
+func IsRetryable(err error) bool {
+	if IsThrottling(err) || IsTimeout(err) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorFault() == smithy.FaultServer
+}
@@ -0,0 +1,13 @@
+module github.com/n-r-w/nerr/nerraws
+
+go 1.19
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.17.3
+	github.com/aws/smithy-go v1.13.5
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,135 @@
+// Package nerrbugsnag - отправка ошибок nerr в Bugsnag
+package nerrbugsnag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bugsnag/bugsnag-go/v2"
+	"github.com/bugsnag/bugsnag-go/v2/errors"
+	"github.com/n-r-w/nerr"
+)
+
+// Tab - вкладка метаданных Bugsnag с кодом, операциями и полями ошибки
+const Tab = "nerr"
+
+// DefaultSeverity - уровень ошибки, если Reporter.Severity не задан
+const DefaultSeverity = "error"
+
+// Reporter отправляет ошибки в Bugsnag. Кадры nerr становятся стеком ошибки, внутренняя операция - классом ошибки,
+// код, операции и поля - метаданными вкладки Tab. Если Notifier не задан, используется bugsnag.Notify
+type Reporter struct {
+	Notifier *bugsnag.Notifier
+
+	// Severity определяет уровень ошибки: "error", "warning" или "info". Если не задан, используется DefaultSeverity
+	Severity func(err error) string
+}
+
+func (r Reporter) Report(ctx context.Context, e *nerr.Error) {
+	severity := DefaultSeverity
+	if r.Severity != nil {
+		severity = r.Severity(e)
+	}
+
+	rawData := []any{ctx, bugsnagSeverity(severity), errorClass(e), MetaData(e)}
+	if r.Notifier != nil {
+		_ = r.Notifier.Notify(withFrames{e}, rawData...)
+		return
+	}
+	_ = bugsnag.Notify(withFrames{e}, rawData...)
+}
+
+// MetaData возвращает метаданные Bugsnag с кодом, операциями и полями ошибки
+func MetaData(err error) bugsnag.MetaData {
+	md := bugsnag.MetaData{}
+	if code := nerr.OutermostCode(err); code != 0 {
+		md.Add(Tab, "code", code)
+	}
+	if ops := nerr.Ops(err); len(ops) > 0 {
+		md.Add(Tab, "ops", ops)
+	}
+	for k, v := range nerr.AllFields(err) {
+		md.Add(Tab, k, v)
+	}
+	return md
+}
+
+func bugsnagSeverity(s string) any {
+	switch s {
+	case "warning":
+		return bugsnag.SeverityWarning
+	case "info":
+		return bugsnag.SeverityInfo
+	default:
+		return bugsnag.SeverityError
+	}
+}
+
+func errorClass(e *nerr.Error) bugsnag.ErrorClass {
+	root := error(e)
+	for {
+		v, ok := root.(*nerr.Error)
+		if !ok || v.Err == nil {
+			break
+		}
+		root = v.Err
+	}
+
+	if _, ok := root.(*nerr.Error); ok {
+		if op := nerr.InnermostOp(e); len(op) > 0 {
+			return bugsnag.ErrorClass{Name: op}
+		}
+	}
+	return bugsnag.ErrorClass{Name: fmt.Sprintf("%T", root)}
+}
+
+// withFrames передает Bugsnag кадры nerr вместо стека места вызова Report
+type withFrames struct {
+	err *nerr.Error
+}
+
+func (w withFrames) Error() string {
+	return w.err.Error()
+}
+
+func (w withFrames) Unwrap() error {
+	return w.err
+}
+
+// StackFrames возвращает кадры цепочки от места возникновения внутренней ошибки к внешним уровням
+func (w withFrames) StackFrames() []errors.StackFrame {
+	var levels [][]nerr.Frame
+	for cur := error(w.err); cur != nil; {
+		e, ok := cur.(*nerr.Error)
+		if !ok {
+			break
+		}
+		levels = append(levels, e.Frames())
+		cur = e.Err
+	}
+
+	var frames []errors.StackFrame
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, f := range levels[i] {
+			pkg, name := splitFunction(f.Function)
+			frames = append(frames, errors.StackFrame{
+				File:       f.File,
+				LineNumber: f.Line,
+				Name:       name,
+				Package:    pkg,
+			})
+		}
+	}
+	return frames
+}
+
+// splitFunction разделяет полное имя функции на пакет и имя: "github.com/a/b.(*T).M" -> "github.com/a/b", "(*T).M"
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/") + 1
+	if dot := strings.Index(name[slash:], "."); dot >= 0 {
+		return name[:slash+dot], name[slash+dot+1:]
+	}
+
+	return "", name
+}
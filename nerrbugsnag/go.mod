@@ -0,0 +1,18 @@
+module github.com/n-r-w/nerr/nerrbugsnag
+
+go 1.19
+
+require (
+	github.com/bugsnag/bugsnag-go/v2 v2.2.0
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require (
+	github.com/bugsnag/panicwrap v1.3.4 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/kardianos/osext v0.0.0-20190222173326-2bc1f35cddc0 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,49 @@
+// Package nerrcli - вывод ошибок nerr в утилитах командной строки на github.com/spf13/cobra
+package nerrcli
+
+import (
+	"fmt"
+
+	"github.com/n-r-w/nerr"
+	"github.com/spf13/cobra"
+)
+
+// FlagVerbose - имя флага, включающего полный вывод ошибки с трассой
+const FlagVerbose = "verbose"
+
+// AddVerboseFlag добавляет команде постоянный флаг --verbose (-v), действующий и для вложенных команд
+func AddVerboseFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolP(FlagVerbose, "v", false, "print full error trace")
+}
+
+// HandleError выводит ошибку в cmd.ErrOrStderr() и возвращает код завершения процесса (nerr.ExitCode).
+// С флагом --verbose выводится полное представление FprintPretty, иначе - одна строка: сообщение для клиента
+// (nerr.UserMessage) или nerr.Compact. Для nil ничего не выводится и возвращается nerr.ExitOK:
+//
+//	if err := root.Execute(); err != nil {
+//		os.Exit(nerrcli.HandleError(root, err))
+//	}
+func HandleError(cmd *cobra.Command, err error) int {
+	if err == nil {
+		return nerr.ExitOK
+	}
+
+	w := cmd.ErrOrStderr()
+	if Verbose(cmd) {
+		_ = nerr.FprintPretty(w, err)
+	} else {
+		msg := nerr.UserMessage(err)
+		if len(msg) == 0 {
+			msg = nerr.Compact(err)
+		}
+		fmt.Fprintln(w, "Error:", msg)
+	}
+
+	return nerr.ExitCode(err)
+}
+
+// Verbose проверяет, что у команды или ее родителей установлен флаг --verbose
+func Verbose(cmd *cobra.Command) bool {
+	f := cmd.Flag(FlagVerbose)
+	return f != nil && f.Value.String() == "true"
+}
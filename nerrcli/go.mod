@@ -0,0 +1,16 @@
+module github.com/n-r-w/nerr/nerrcli
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/spf13/cobra v1.6.1
+)
+
+require (
+	github.com/inconshreveable/mousetrap v1.0.1 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
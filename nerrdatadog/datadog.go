@@ -0,0 +1,122 @@
+// Package nerrdatadog - вывод ошибок nerr в формате Datadog Error Tracking.
+// Атрибуты error.kind, error.message и error.stack добавляются в запись журнала, чтобы Datadog группировал ошибки
+package nerrdatadog
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Атрибуты журнала, по которым Datadog Error Tracking распознает ошибку и связывает ее с трассой
+const (
+	KeyKind    = "error.kind"
+	KeyMessage = "error.message"
+	KeyStack   = "error.stack"
+	KeyTraceID = "dd.trace_id"
+	KeySpanID  = "dd.span_id"
+)
+
+// Поля ошибки с идентификаторами трассы и спана OpenTelemetry (добавляются nerrotel при создании через nerr.NewCtx)
+const (
+	FieldTraceID = "trace_id"
+	FieldSpanID  = "span_id"
+)
+
+// Attrs возвращает атрибуты ошибки для журнала: тип, текст, стек в формате Go и, если в ошибке есть поля трассы,
+// идентификаторы dd.trace_id и dd.span_id
+func Attrs(err error) map[string]any {
+	if err == nil {
+		return nil
+	}
+
+	attrs := map[string]any{
+		KeyKind:    Kind(err),
+		KeyMessage: err.Error(),
+	}
+
+	if stack := Stack(err); len(stack) > 0 {
+		attrs[KeyStack] = stack
+	}
+
+	if id, ok := fieldID(err, FieldTraceID); ok {
+		attrs[KeyTraceID] = id
+	}
+	if id, ok := fieldID(err, FieldSpanID); ok {
+		attrs[KeySpanID] = id
+	}
+
+	return attrs
+}
+
+// Kind возвращает тип ошибки для группировки: внутреннюю операцию, а для сторонней первопричины - ее тип Go
+func Kind(err error) string {
+	root := err
+	for {
+		e, ok := root.(*nerr.Error)
+		if !ok || e.Err == nil {
+			break
+		}
+		root = e.Err
+	}
+
+	if _, ok := root.(*nerr.Error); ok {
+		if op := nerr.InnermostOp(err); len(op) > 0 {
+			return op
+		}
+	}
+	return fmt.Sprintf("%T", root)
+}
+
+// Stack возвращает кадры цепочки в формате стека Go: от места возникновения внутренней ошибки к внешним уровням
+func Stack(err error) string {
+	var levels [][]nerr.Frame
+	for cur := err; cur != nil; {
+		e, ok := cur.(*nerr.Error)
+		if !ok {
+			break
+		}
+		levels = append(levels, e.Frames())
+		cur = e.Err
+	}
+
+	var b strings.Builder
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, f := range levels[i] {
+			if f == nerr.RemoteFrame {
+				b.WriteString(f.Function + "\n")
+				continue
+			}
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		}
+	}
+	return b.String()
+}
+
+// fieldID преобразует шестнадцатеричный идентификатор OpenTelemetry из поля ошибки в десятичный идентификатор Datadog:
+// Datadog использует младшие 64 бита
+func fieldID(err error, key string) (string, bool) {
+	v, ok := nerr.Field(err, key)
+	if !ok {
+		return "", false
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+
+	raw, decodeErr := hex.DecodeString(s)
+	if decodeErr != nil || len(raw) < 8 {
+		return "", false
+	}
+
+	var id uint64
+	for _, c := range raw[len(raw)-8:] {
+		id = id<<8 | uint64(c)
+	}
+	return strconv.FormatUint(id, 10), true
+}
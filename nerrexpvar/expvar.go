@@ -0,0 +1,58 @@
+// Package nerrexpvar - счетчики ошибок nerr в expvar для сервисов без Prometheus
+package nerrexpvar
+
+import (
+	"context"
+	"expvar"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Counters - счетчики ошибок: общее количество (total), по кодам (code) и по операциям (op)
+type Counters struct {
+	total  *expvar.Int
+	byCode *expvar.Map
+	byOp   *expvar.Map
+
+	// Labels ограничивает ключи счетчиков code и op. По умолчанию операции нормализуются через nerr.NormalizeOp
+	Labels nerr.MetricLabels
+}
+
+// Publish публикует счетчики в expvar под именем name. Повторная публикация с тем же именем вызывает панику
+func Publish(name string) *Counters {
+	c := &Counters{
+		total:  new(expvar.Int),
+		byCode: new(expvar.Map).Init(),
+		byOp:   new(expvar.Map).Init(),
+	}
+
+	m := expvar.NewMap(name)
+	m.Set("total", c.total)
+	m.Set("code", c.byCode)
+	m.Set("op", c.byOp)
+
+	return c
+}
+
+// Install подписывает счетчики на создание всех ошибок nerr
+func (c *Counters) Install() {
+	nerr.AddHook(func(e *nerr.Error) {
+		c.Observe(e)
+	})
+}
+
+// Report позволяет использовать счетчики как nerr.Reporter
+func (c *Counters) Report(_ context.Context, e *nerr.Error) {
+	c.Observe(e)
+}
+
+// Observe учитывает ошибку в счетчиках
+func (c *Counters) Observe(err error) {
+	if err == nil {
+		return
+	}
+
+	c.total.Add(1)
+	c.byCode.Add(c.Labels.Code(err), 1)
+	c.byOp.Add(c.Labels.Op(err), 1)
+}
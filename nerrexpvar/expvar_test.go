@@ -0,0 +1,48 @@
+package nerrexpvar_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"reflect"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrexpvar"
+)
+
+// published возвращает значения счетчиков, опубликованных под именем name
+func published(t *testing.T, name string) map[string]any {
+	t.Helper()
+
+	var res map[string]any
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestCounters(t *testing.T) {
+	c := nerrexpvar.Publish("nerrexpvar_test")
+	c.Labels = nerr.MetricLabels{Codes: []int{nerr.ErrNotFound}}
+
+	c.Observe(nerr.New("user.42.load", nerr.ErrNotFound))
+	c.Observe(nerr.New("user.7.load", nerr.ErrNotFound))
+	c.Report(context.Background(), nerr.New("save", nerr.ErrConflict).(*nerr.Error))
+	c.Observe(nil)
+
+	nerr.Configure(nerr.WithoutHooks())
+	c.Install()
+	_ = nerr.New("save", 5001)
+	nerr.Configure(nerr.WithoutHooks())
+	_ = nerr.New("ignored", 5001)
+
+	want := map[string]any{
+		"total": float64(4),
+		"code":  map[string]any{"9001": float64(2), "other": float64(2)},
+		"op":    map[string]any{"user.{id}.load": float64(2), "save": float64(2)},
+	}
+	if got := published(t, "nerrexpvar_test"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("counters = %v, want %v", got, want)
+	}
+}
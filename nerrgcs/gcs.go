@@ -0,0 +1,38 @@
+// Package nerrgcs - классификация ошибок Google Cloud Storage.
+// Импорт пакета регистрирует правила nerr.MapError для ошибок GCS
+package nerrgcs
+
+import (
+	"errors"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"github.com/n-r-w/nerr"
+	"google.golang.org/api/googleapi"
+)
+
+func init() {
+	nerr.MapError(IsNotFound, nerr.ErrNotFound)
+	nerr.MapError(IsPreconditionFailed, nerr.ErrConflict)
+}
+
+// HTTPStatus возвращает HTTP статус ответа API или 0
+func HTTPStatus(err error) int {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+// IsNotFound проверяет отсутствие объекта или бакета
+func IsNotFound(err error) bool {
+	return errors.Is(err, storage.ErrObjectNotExist) ||
+		errors.Is(err, storage.ErrBucketNotExist) ||
+		HTTPStatus(err) == http.StatusNotFound
+}
+
+// IsPreconditionFailed проверяет невыполнение условия запроса (generation match и т.п., статус 412)
+func IsPreconditionFailed(err error) bool {
+	return HTTPStatus(err) == http.StatusPreconditionFailed
+}
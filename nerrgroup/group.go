@@ -0,0 +1,110 @@
+// Package nerrgroup - аналог golang.org/x/sync/errgroup, который может собирать ошибки всех горутин в nerr.MultiError
+package nerrgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Group - набор горутин, выполняющих подзадачи одной задачи. Нулевое значение готово к использованию
+type Group struct {
+	cancel func()
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	collectAll bool
+	errOnce    sync.Once
+	err        error
+	errs       nerr.Collector
+}
+
+// WithContext возвращает новую группу и производный контекст, отменяемый при первой ошибке или по завершении Wait
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetCollectAll включает сбор ошибок всех горутин: Wait вернет nerr.MultiError вместо первой ошибки.
+// Контекст по-прежнему отменяется при первой ошибке
+func (g *Group) SetCollectAll(collect bool) {
+	g.collectAll = collect
+}
+
+// SetLimit ограничивает количество одновременно работающих горутин. n < 0 снимает ограничение.
+// Нельзя менять ограничение, пока в группе есть активные горутины
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("nerrgroup: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go запускает f в новой горутине, ожидая освобождения места, если задано ограничение
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go g.run(f)
+}
+
+// TryGo запускает f, только если не превышено ограничение количества горутин
+func (g *Group) TryGo(f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+
+	g.wg.Add(1)
+	go g.run(f)
+	return true
+}
+
+// Wait ожидает завершения всех горутин и возвращает первую ошибку или, при SetCollectAll, все ошибки
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	if g.collectAll {
+		return g.errs.Err()
+	}
+	return g.err
+}
+
+func (g *Group) run(f func() error) {
+	defer g.done()
+
+	if err := f(); err != nil {
+		if g.collectAll {
+			g.errs.Add(err)
+		}
+
+		g.errOnce.Do(func() {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel()
+			}
+		})
+	}
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,99 @@
+package nerrgroup_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrgroup"
+)
+
+func TestGroupFirstError(t *testing.T) {
+	errA := errors.New("a")
+
+	g, ctx := nerrgroup.WithContext(context.Background())
+	g.Go(func() error { return errA })
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := g.Wait(); err != errA {
+		t.Fatalf("Wait() = %v, want %v", err, errA)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("context not canceled")
+	}
+}
+
+func TestGroupCollectAll(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	var g nerrgroup.Group
+	g.SetCollectAll(true)
+	g.Go(func() error { return errA })
+	g.Go(func() error { return nil })
+	g.Go(func() error { return errB })
+
+	err := g.Wait()
+
+	var m *nerr.MultiError
+	if !errors.As(err, &m) || len(m.Errors) != 2 {
+		t.Fatalf("Wait() = %#v, want MultiError with 2 errors", err)
+	}
+	for _, want := range []error{errA, errB} {
+		found := false
+		for _, e := range m.Errors {
+			if e == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%v not found in %v", want, err)
+		}
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	var g nerrgroup.Group
+	g.SetLimit(1)
+
+	// track отмечает наибольшее количество одновременно работающих горутин
+	var active, peak int32
+	track := func(wait <-chan struct{}) func() error {
+		return func() error {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			if n > atomic.LoadInt32(&peak) {
+				atomic.StoreInt32(&peak, n)
+			}
+			<-wait
+			return nil
+		}
+	}
+
+	release := make(chan struct{})
+	g.Go(track(release))
+
+	if g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo() = true over the limit")
+	}
+	close(release)
+
+	for i := 0; i < 10; i++ {
+		g.Go(track(release))
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if peak != 1 {
+		t.Fatalf("peak = %d, want 1", peak)
+	}
+	if !g.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo() = false after Wait")
+	}
+	_ = g.Wait()
+}
@@ -0,0 +1,21 @@
+// Package franz подключает распознавание ошибок github.com/twmb/franz-go в nerrkafka
+package franz
+
+import (
+	"errors"
+
+	"github.com/n-r-w/nerr/nerrkafka"
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+func init() {
+	nerrkafka.RegisterExtractor(code)
+}
+
+func code(err error) (int16, bool) {
+	var e *kerr.Error
+	if errors.As(err, &e) {
+		return e.Code, true
+	}
+	return 0, false
+}
@@ -0,0 +1,12 @@
+module github.com/n-r-w/nerr/nerrkafka/franz
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/twmb/franz-go v1.11.5
+)
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ../..
@@ -0,0 +1,109 @@
+// Package nerrkafka - классификация ошибок брокера Kafka по кодам протокола.
+// Ошибки клиентов распознаются после импорта nerrkafka/sarama или nerrkafka/franz.
+// Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable для ошибок Kafka
+package nerrkafka
+
+import (
+	"sync"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Коды ошибок протокола Kafka
+const (
+	OffsetOutOfRange             int16 = 1
+	UnknownTopicOrPartition      int16 = 3
+	LeaderNotAvailable           int16 = 5
+	NotLeaderOrFollower          int16 = 6
+	RequestTimedOut              int16 = 7
+	MessageTooLarge              int16 = 10
+	NetworkException             int16 = 13
+	CoordinatorLoadInProgress    int16 = 14
+	CoordinatorNotAvailable      int16 = 15
+	NotCoordinator               int16 = 16
+	NotEnoughReplicas            int16 = 19
+	NotEnoughReplicasAfterAppend int16 = 20
+	RebalanceInProgress          int16 = 27
+	TopicAuthorizationFailed     int16 = 29
+	KafkaStorageError            int16 = 56
+	ThrottlingQuotaExceeded      int16 = 89
+)
+
+var retryable = map[int16]bool{
+	LeaderNotAvailable:           true,
+	NotLeaderOrFollower:          true,
+	RequestTimedOut:              true,
+	NetworkException:             true,
+	CoordinatorLoadInProgress:    true,
+	CoordinatorNotAvailable:      true,
+	NotCoordinator:               true,
+	NotEnoughReplicas:            true,
+	NotEnoughReplicasAfterAppend: true,
+	RebalanceInProgress:          true,
+	KafkaStorageError:            true,
+	ThrottlingQuotaExceeded:      true,
+}
+
+var codes = map[int16]int{
+	UnknownTopicOrPartition:      nerr.ErrNotFound,
+	RequestTimedOut:              nerr.ErrTimeout,
+	LeaderNotAvailable:           nerr.ErrUnavailable,
+	NotLeaderOrFollower:          nerr.ErrUnavailable,
+	NetworkException:             nerr.ErrUnavailable,
+	CoordinatorLoadInProgress:    nerr.ErrUnavailable,
+	CoordinatorNotAvailable:      nerr.ErrUnavailable,
+	NotCoordinator:               nerr.ErrUnavailable,
+	NotEnoughReplicas:            nerr.ErrUnavailable,
+	NotEnoughReplicasAfterAppend: nerr.ErrUnavailable,
+	KafkaStorageError:            nerr.ErrUnavailable,
+}
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []func(err error) (int16, bool)
+)
+
+func init() {
+	for _, code := range []int{nerr.ErrNotFound, nerr.ErrTimeout, nerr.ErrUnavailable} {
+		code := code
+		nerr.MapError(func(err error) bool { return NerrCode(err) == code }, code)
+	}
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// RegisterExtractor регистрирует функцию, извлекающую код протокола из ошибки клиента. Функция получает всю цепочку
+// и ищет в ней ошибку клиента через errors.As. Вызывать при инициализации
+func RegisterExtractor(fn func(err error) (int16, bool)) {
+	extractorsMu.Lock()
+	extractors = append(extractors, fn)
+	extractorsMu.Unlock()
+}
+
+// Code возвращает код ошибки протокола Kafka из любого места цепочки или 0
+func Code(err error) int16 {
+	if err == nil {
+		return 0
+	}
+
+	// элементы списка не изменяются, поэтому после чтения среза блокировка не нужна
+	extractorsMu.RLock()
+	list := extractors
+	extractorsMu.RUnlock()
+
+	for _, fn := range list {
+		if code, ok := fn(err); ok {
+			return code
+		}
+	}
+	return 0
+}
+
+// NerrCode возвращает код nerr, соответствующий ошибке Kafka, или 0
+func NerrCode(err error) int {
+	return codes[Code(err)]
+}
+
+// IsRetryable проверяет, что запрос к брокеру можно повторить
+func IsRetryable(err error) bool {
+	return retryable[Code(err)]
+}
@@ -0,0 +1,63 @@
+//go:build go1.20
+
+// errors.As обходит MultiError (Unwrap() []error) начиная с Go 1.20
+
+package nerrkafka_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrkafka"
+)
+
+// brokerError - ошибка клиента с кодом протокола Kafka
+type brokerError int16
+
+func (e brokerError) Error() string { return fmt.Sprintf("kafka error %d", int16(e)) }
+
+func init() {
+	nerrkafka.RegisterExtractor(func(err error) (int16, bool) {
+		var e brokerError
+		if errors.As(err, &e) {
+			return int16(e), true
+		}
+		return 0, false
+	})
+}
+
+func TestCode(t *testing.T) {
+	notLeader := brokerError(nerrkafka.NotLeaderOrFollower)
+
+	tests := []struct {
+		name          string
+		err           error
+		want          int16
+		wantNerr      int
+		wantRetryable bool
+	}{
+		{"nil", nil, 0, 0, false},
+		{"not kafka", errors.New("plain"), 0, 0, false},
+		{"direct", notLeader, nerrkafka.NotLeaderOrFollower, nerr.ErrUnavailable, true},
+		{"nerr nested", nerr.New("produce", nerr.New("send", notLeader)), nerrkafka.NotLeaderOrFollower, nerr.ErrUnavailable, true},
+		{"fmt over nerr", fmt.Errorf("handler: %w", nerr.New("send", notLeader)), nerrkafka.NotLeaderOrFollower, nerr.ErrUnavailable, true},
+		{"multi", &nerr.MultiError{Errors: []error{errors.New("other"), nerr.New("send", notLeader)}}, nerrkafka.NotLeaderOrFollower, nerr.ErrUnavailable, true},
+		{"join", errors.Join(errors.New("other"), brokerError(nerrkafka.MessageTooLarge)), nerrkafka.MessageTooLarge, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nerrkafka.Code(tt.err); got != tt.want {
+				t.Fatalf("Code() = %d, want %d", got, tt.want)
+			}
+			if got := nerrkafka.NerrCode(tt.err); got != tt.wantNerr {
+				t.Fatalf("NerrCode() = %d, want %d", got, tt.wantNerr)
+			}
+			if got := nerrkafka.IsRetryable(tt.err); got != tt.wantRetryable {
+				t.Fatalf("IsRetryable() = %v, want %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
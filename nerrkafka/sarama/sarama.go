@@ -0,0 +1,21 @@
+// Package sarama подключает распознавание ошибок github.com/Shopify/sarama в nerrkafka
+package sarama
+
+import (
+	"errors"
+
+	"github.com/Shopify/sarama"
+	"github.com/n-r-w/nerr/nerrkafka"
+)
+
+func init() {
+	nerrkafka.RegisterExtractor(code)
+}
+
+func code(err error) (int16, bool) {
+	var e sarama.KError
+	if errors.As(err, &e) {
+		return int16(e), true
+	}
+	return 0, false
+}
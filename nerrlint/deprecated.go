@@ -0,0 +1,151 @@
+package nerrlint
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// deprecatedFact - объект или пакет модуля nerr, в документации которого есть абзац "Deprecated: ..."
+type deprecatedFact struct {
+	Message string
+}
+
+func (*deprecatedFact) AFact() {}
+
+func (f *deprecatedFact) String() string {
+	return "deprecated: " + f.Message
+}
+
+// exportDeprecated отмечает фактами устаревшие объекты и сам пакет, если пакет относится к модулю nerr
+func exportDeprecated(pass *analysis.Pass) {
+	if !inModule(pass.Pkg) {
+		return
+	}
+
+	for _, file := range pass.Files {
+		if msg, ok := deprecation(file.Doc); ok {
+			pass.ExportPackageFact(&deprecatedFact{Message: msg})
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				exportObject(pass, d.Name, d.Doc)
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						exportObject(pass, s.Name, docOf(s.Doc, d))
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							exportObject(pass, name, docOf(s.Doc, d))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func exportObject(pass *analysis.Pass, name *ast.Ident, doc *ast.CommentGroup) {
+	msg, ok := deprecation(doc)
+	if !ok {
+		return
+	}
+	if obj := pass.TypesInfo.Defs[name]; obj != nil {
+		pass.ExportObjectFact(obj, &deprecatedFact{Message: msg})
+	}
+}
+
+// docOf возвращает документацию спецификации, а для объявления из одной спецификации - документацию объявления
+func docOf(doc *ast.CommentGroup, d *ast.GenDecl) *ast.CommentGroup {
+	if doc == nil && !d.Lparen.IsValid() {
+		return d.Doc
+	}
+	return doc
+}
+
+// deprecation возвращает текст абзаца "Deprecated:" документации
+func deprecation(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+
+	for _, p := range strings.Split(doc.Text(), "\n\n") {
+		if msg, ok := strings.CutPrefix(p, "Deprecated:"); ok {
+			return strings.Join(strings.Fields(msg), " "), true
+		}
+	}
+	return "", false
+}
+
+// checkDeprecated сообщает об импорте устаревших пакетов и использовании устаревших объектов модуля nerr.
+// Для известных замен (см. migrations) предлагается исправление
+func checkDeprecated(pass *analysis.Pass) {
+	for _, file := range pass.Files {
+		for _, spec := range file.Imports {
+			pkg := importedPackage(pass, spec)
+			if pkg == nil || !inModule(pkg) {
+				continue
+			}
+
+			var fact deprecatedFact
+			if pass.ImportPackageFact(pkg, &fact) {
+				pass.Reportf(spec.Pos(), "package %s is deprecated: %s", pkg.Path(), fact.Message)
+			}
+		}
+	}
+
+	for _, id := range moduleUses(pass) {
+		obj := pass.TypesInfo.Uses[id]
+
+		var fact deprecatedFact
+		if !pass.ImportObjectFact(obj, &fact) {
+			continue
+		}
+
+		d := analysis.Diagnostic{
+			Pos:     id.Pos(),
+			End:     id.End(),
+			Message: obj.Name() + " is deprecated: " + fact.Message,
+		}
+		if fix, ok := migrationFix(pass, id, obj); ok {
+			d.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		pass.Report(d)
+	}
+}
+
+func importedPackage(pass *analysis.Pass, spec *ast.ImportSpec) *types.Package {
+	var obj types.Object
+	if spec.Name != nil {
+		obj = pass.TypesInfo.Defs[spec.Name]
+	} else {
+		obj = pass.TypesInfo.Implicits[spec]
+	}
+
+	if name, ok := obj.(*types.PkgName); ok {
+		return name.Imported()
+	}
+	return nil
+}
+
+// moduleUses возвращает упорядоченные по позиции идентификаторы, ссылающиеся на объекты других пакетов модуля nerr
+func moduleUses(pass *analysis.Pass) []*ast.Ident {
+	var res []*ast.Ident
+	for id, obj := range pass.TypesInfo.Uses {
+		if obj.Pkg() != nil && obj.Pkg() != pass.Pkg && inModule(obj.Pkg()) {
+			res = append(res, id)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Pos() < res[j].Pos() })
+	return res
+}
+
+func inModule(pkg *types.Package) bool {
+	return pkg.Path() == nerrPath || strings.HasPrefix(pkg.Path(), nerrPath+"/")
+}
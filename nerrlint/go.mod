@@ -0,0 +1,5 @@
+module github.com/n-r-w/nerr/nerrlint
+
+go 1.19
+
+require golang.org/x/tools v0.5.0
@@ -0,0 +1,178 @@
+package nerrlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// MigrateAnalyzer предлагает только исправления устаревших вызовов nerr, для которых известна замена:
+//   - nerr.NewLevel(n, ...) -> nerr.New(nerr.Skip(n-1), ...);
+//   - nerr.TopCode(err) и err.TopCode() -> nerr.OutermostCode(err).
+//
+// Используется командой nerrmigrate: nerrmigrate -fix ./...
+var MigrateAnalyzer = &analysis.Analyzer{
+	Name: "nerrmigrate",
+	Doc:  "rewrite deprecated nerr calls to their replacements",
+	Run:  runMigrate,
+}
+
+func runMigrate(pass *analysis.Pass) (any, error) {
+	for _, id := range moduleUses(pass) {
+		if fix, ok := migrationFix(pass, id, pass.TypesInfo.Uses[id]); ok {
+			pass.Report(analysis.Diagnostic{
+				Pos:            id.Pos(),
+				End:            id.End(),
+				Message:        fix.Message,
+				SuggestedFixes: []analysis.SuggestedFix{fix},
+			})
+		}
+	}
+	return nil, nil
+}
+
+// migrationFix возвращает исправление использования устаревшего объекта id, если замена известна
+func migrationFix(pass *analysis.Pass, id *ast.Ident, obj types.Object) (analysis.SuggestedFix, bool) {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return analysis.SuggestedFix{}, false
+	}
+
+	switch fn.FullName() {
+	case nerrPath + ".TopCode":
+		return analysis.SuggestedFix{
+			Message:   "use nerr.OutermostCode",
+			TextEdits: []analysis.TextEdit{{Pos: id.Pos(), End: id.End(), NewText: []byte("OutermostCode")}},
+		}, true
+
+	case "(*" + nerrPath + ".Error).TopCode":
+		file := fileOf(pass, id.Pos())
+		call := callOf(file, id)
+		qual, imported := nerrQualifier(file)
+		if call == nil || !imported {
+			return analysis.SuggestedFix{}, false
+		}
+
+		recv := call.Fun.(*ast.SelectorExpr).X
+		return analysis.SuggestedFix{
+			Message: "use nerr.OutermostCode",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     call.Pos(),
+				End:     call.End(),
+				NewText: []byte(qual + "OutermostCode(" + types.ExprString(recv) + ")"),
+			}},
+		}, true
+
+	case nerrPath + ".NewLevel":
+		call := callOf(fileOf(pass, id.Pos()), id)
+		if call == nil || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			return analysis.SuggestedFix{}, false
+		}
+		return newLevelFix(pass, id, call), true
+	}
+
+	return analysis.SuggestedFix{}, false
+}
+
+// newLevelFix заменяет NewLevel(n, ...) на New(Skip(n-1), ...). NewLevel(1, ...) становится New(...)
+func newLevelFix(pass *analysis.Pass, id *ast.Ident, call *ast.CallExpr) analysis.SuggestedFix {
+	var qual string
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		qual = types.ExprString(sel.X) + "."
+	}
+
+	level := call.Args[0]
+	edits := []analysis.TextEdit{{Pos: id.Pos(), End: id.End(), NewText: []byte("New")}}
+
+	var skip string
+	if tv, ok := pass.TypesInfo.Types[level]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+		if n, ok := constant.Int64Val(tv.Value); ok {
+			skip = strconv.FormatInt(n-1, 10)
+		}
+	}
+
+	switch {
+	case skip == "0":
+		end := level.End()
+		if len(call.Args) > 1 {
+			end = call.Args[1].Pos()
+		}
+		edits = append(edits, analysis.TextEdit{Pos: level.Pos(), End: end})
+	case len(skip) > 0:
+		edits = append(edits, analysis.TextEdit{Pos: level.Pos(), End: level.End(), NewText: []byte(qual + "Skip(" + skip + ")")})
+	default:
+		edits = append(edits, analysis.TextEdit{
+			Pos:     level.Pos(),
+			End:     level.End(),
+			NewText: []byte(qual + "Skip(" + types.ExprString(level) + " - 1)"),
+		})
+	}
+
+	return analysis.SuggestedFix{Message: "use nerr.New with nerr.Skip", TextEdits: edits}
+}
+
+func fileOf(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, f := range pass.Files {
+		if f.Pos() <= pos && pos < f.End() {
+			return f
+		}
+	}
+	return nil
+}
+
+// callOf возвращает вызов, в котором id - вызываемая функция или метод
+func callOf(file *ast.File, id *ast.Ident) *ast.CallExpr {
+	if file == nil {
+		return nil
+	}
+
+	var res *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if res != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			if fun == id {
+				res = call
+			}
+		case *ast.SelectorExpr:
+			if fun.Sel == id {
+				res = call
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// nerrQualifier возвращает префикс для обращения к пакету nerr в файле и false, если пакет не импортирован
+func nerrQualifier(file *ast.File) (string, bool) {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != nerrPath {
+			continue
+		}
+
+		switch {
+		case spec.Name == nil:
+			return "nerr.", true
+		case spec.Name.Name == ".":
+			return "", true
+		case spec.Name.Name == "_":
+			return "", false
+		default:
+			return spec.Name.Name + ".", true
+		}
+	}
+	return "", false
+}
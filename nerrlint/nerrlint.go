@@ -0,0 +1,294 @@
+// Package nerrlint - анализатор go/analysis для проверки использования nerr.
+//
+// Проверяет:
+//   - вызовы nerr.New и nerr.NewLevel без операции и кода;
+//   - аргументы nerr.New, nerr.NewLevel и nerr.NewCtx, которые приводят к панике во время выполнения:
+//     неподдерживаемые типы, несколько кодов, несколько ошибок;
+//   - константы кодов ошибок пакета с совпадающими значениями;
+//   - использование устаревших пакетов и объектов модуля nerr (абзац "Deprecated:" в документации)
+//     с исправлением для известных замен (см. MigrateAnalyzer).
+//
+// Запуск через go vet: go vet -vettool=$(which nerrlint) ./...
+package nerrlint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	nerrPath = "github.com/n-r-w/nerr"
+	enoPath  = "github.com/n-r-w/eno"
+)
+
+// Analyzer проверяет вызовы конструкторов nerr и константы кодов ошибок
+var Analyzer = &analysis.Analyzer{
+	Name:      "nerrlint",
+	Doc:       "check nerr.New arguments, error code constants and deprecated nerr API",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(deprecatedFact)},
+}
+
+// argKind - как prepareProperty обработает аргумент
+type argKind int
+
+const (
+	argUnknown argKind = iota
+	argNil
+	argOp
+	argCode
+	argErrNo
+	argErrors
+	argContext
+	argFields
+	argSkip
+	argError
+	argInvalid
+)
+
+var (
+	errorType      = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	errorSlice     = types.NewSlice(types.Universe.Lookup("error").Type())
+	anySlice       = types.NewSlice(types.NewInterfaceType(nil, nil).Complete())
+	fieldsMap      = types.NewMap(types.Typ[types.String], types.NewInterfaceType(nil, nil).Complete())
+	contextMethods = []string{"Deadline", "Done", "Err", "Value"}
+)
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	exportDeprecated(pass)
+	checkDeprecated(pass)
+
+	codeConsts := map[*types.Const]bool{}
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+
+		fn := typeutil.StaticCallee(pass.TypesInfo, call)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != nerrPath {
+			return
+		}
+
+		var skip int
+		switch fn.Name() {
+		case "New":
+		case "NewLevel", "NewCtx":
+			skip = 1
+		default:
+			return
+		}
+
+		if call.Ellipsis.IsValid() || len(call.Args) < skip {
+			return
+		}
+
+		checkArgs(pass, call, fn.Name(), call.Args[skip:], codeConsts)
+	})
+
+	checkDuplicateCodes(pass, codeConsts)
+
+	return nil, nil
+}
+
+func checkArgs(pass *analysis.Pass, call *ast.CallExpr, name string, args []ast.Expr, codeConsts map[*types.Const]bool) {
+	var (
+		hasOp, hasCode, unknown bool
+		codes, errs             int
+	)
+
+	for _, arg := range args {
+		tv, ok := pass.TypesInfo.Types[arg]
+		if !ok {
+			unknown = true
+			continue
+		}
+
+		switch classify(tv.Type) {
+		case argOp:
+			hasOp = true
+		case argCode:
+			hasCode = true
+			codes++
+			if c := constObject(pass, arg); c != nil {
+				codeConsts[c] = true
+			}
+		case argErrNo:
+			hasOp, hasCode = true, true
+			if c := constObject(pass, arg); c != nil {
+				codeConsts[c] = true
+			}
+		case argError:
+			errs++
+		case argInvalid:
+			pass.Reportf(arg.Pos(), "nerr.%s: argument of type %s is not supported and panics at runtime", name, tv.Type)
+		case argUnknown:
+			unknown = true
+		}
+	}
+
+	if codes > 1 {
+		pass.Reportf(call.Pos(), "nerr.%s: more than one int code panics at runtime", name)
+	}
+	if errs > 1 {
+		pass.Reportf(call.Pos(), "nerr.%s: more than one error panics at runtime", name)
+	}
+
+	// у NewCtx операция может прийти из контекста (nerr.PushOp)
+	if name != "NewCtx" && !hasOp && !hasCode && !unknown {
+		pass.Reportf(call.Pos(), "nerr.%s without op or code", name)
+	}
+}
+
+// classify повторяет порядок выбора в prepareProperty
+func classify(t types.Type) argKind {
+	if b, ok := t.(*types.Basic); ok {
+		switch b.Kind() {
+		case types.UntypedNil:
+			return argNil
+		case types.String, types.UntypedString:
+			return argOp
+		}
+		if b.Info()&types.IsInteger != 0 {
+			return argCode
+		}
+		return argInvalid
+	}
+
+	if isNamed(t, enoPath, "ErrNo") {
+		return argErrNo
+	}
+
+	if isNamed(t, nerrPath, "Skip") {
+		return argSkip
+	}
+
+	if types.Identical(t, errorSlice) || types.Identical(t, anySlice) {
+		return argErrors
+	}
+
+	if types.Identical(t, fieldsMap) {
+		return argFields
+	}
+
+	if isContext(t) {
+		return argContext
+	}
+
+	if types.Implements(t, errorType) {
+		return argError
+	}
+
+	// именованные целочисленные типы преобразуются в код, а с методом String() - еще и в операцию
+	if b, ok := t.Underlying().(*types.Basic); ok && b.Info()&types.IsInteger != 0 {
+		if isStringer(t) {
+			return argErrNo
+		}
+		return argCode
+	}
+
+	if types.IsInterface(t) {
+		// динамический тип известен только во время выполнения
+		return argUnknown
+	}
+
+	return argInvalid
+}
+
+func isStringer(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "String")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+func isContext(t types.Type) bool {
+	if isNamed(t, "context", "Context") {
+		return true
+	}
+
+	// любой тип, реализующий context.Context, распознается как контекст
+	for _, m := range contextMethods {
+		if _, ok := lookupMethod(t, m).(*types.Func); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupMethod(t types.Type, name string) types.Object {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+	return obj
+}
+
+// constObject возвращает константу пакета, если аргумент - ссылка на нее
+func constObject(pass *analysis.Pass, arg ast.Expr) *types.Const {
+	var id *ast.Ident
+	switch v := arg.(type) {
+	case *ast.Ident:
+		id = v
+	case *ast.SelectorExpr:
+		id = v.Sel
+	default:
+		return nil
+	}
+
+	c, ok := pass.TypesInfo.Uses[id].(*types.Const)
+	if !ok || c.Pkg() != pass.Pkg || c.Parent() != pass.Pkg.Scope() {
+		return nil
+	}
+	return c
+}
+
+// checkDuplicateCodes сообщает о константах пакета с одинаковыми значениями, используемых как коды,
+// а также о константах типа eno.ErrNo
+func checkDuplicateCodes(pass *analysis.Pass, used map[*types.Const]bool) {
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && isNamed(c.Type(), enoPath, "ErrNo") {
+			used[c] = true
+		}
+	}
+
+	consts := make([]*types.Const, 0, len(used))
+	for c := range used {
+		consts = append(consts, c)
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	seen := map[int64]*types.Const{}
+	for _, c := range consts {
+		v, ok := constant.Int64Val(c.Val())
+		if !ok {
+			continue
+		}
+
+		if first, ok := seen[v]; ok {
+			pass.Reportf(c.Pos(), "error code %s has the same value %d as %s", c.Name(), v, first.Name())
+			continue
+		}
+		seen[v] = c
+	}
+}
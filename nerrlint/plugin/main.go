@@ -0,0 +1,15 @@
+// Плагин golangci-lint для nerrlint: go build -buildmode=plugin -o nerrlint.so ./nerrlint/plugin
+package main
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/n-r-w/nerr/nerrlint"
+)
+
+// New вызывается golangci-lint при загрузке плагина
+func New(conf any) ([]*analysis.Analyzer, error) {
+	return []*analysis.Analyzer{nerrlint.Analyzer}, nil
+}
+
+func main() {}
@@ -0,0 +1,94 @@
+// Package nerrmongo - классификация ошибок go.mongodb.org/mongo-driver.
+// Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable для ошибок MongoDB
+package nerrmongo
+
+import (
+	"errors"
+
+	"github.com/n-r-w/nerr"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Метка ошибок MongoDB, после которых транзакцию можно повторить
+const labelTransientTransaction = "TransientTransactionError"
+
+func init() {
+	nerr.MapError(IsNotFound, nerr.ErrNotFound)
+	nerr.MapError(IsDuplicateKey, nerr.ErrConflict)
+	nerr.MapError(IsTimeout, nerr.ErrTimeout)
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// Codes возвращает коды ошибок сервера из любого места цепочки: код CommandError,
+// коды всех ошибок записи и ошибки write concern в WriteException и BulkWriteException
+func Codes(err error) []int {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return []int{int(cmdErr.Code)}
+	}
+
+	var res []int
+
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		for _, we := range writeErr.WriteErrors {
+			res = append(res, we.Code)
+		}
+		if writeErr.WriteConcernError != nil {
+			res = append(res, writeErr.WriteConcernError.Code)
+		}
+		return res
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, we := range bulkErr.WriteErrors {
+			res = append(res, we.Code)
+		}
+		if bulkErr.WriteConcernError != nil {
+			res = append(res, bulkErr.WriteConcernError.Code)
+		}
+	}
+
+	return res
+}
+
+// HasCode проверяет наличие кода ошибки сервера в цепочке
+func HasCode(err error, code int) bool {
+	for _, c := range Codes(err) {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound проверяет, что документ не найден (mongo.ErrNoDocuments)
+func IsNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// IsDuplicateKey проверяет нарушение уникального индекса (11000 и аналоги)
+func IsDuplicateKey(err error) bool {
+	return mongo.IsDuplicateKeyError(err)
+}
+
+// IsTimeout проверяет истечение времени ожидания операции
+func IsTimeout(err error) bool {
+	return mongo.IsTimeout(err)
+}
+
+// IsNetwork проверяет сетевую ошибку
+func IsNetwork(err error) bool {
+	return mongo.IsNetworkError(err)
+}
+
+// IsRetryable проверяет, что операцию можно повторить: сетевая ошибка, таймаут или временная ошибка транзакции
+func IsRetryable(err error) bool {
+	if IsNetwork(err) || IsTimeout(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorLabel(labelTransientTransaction)
+}
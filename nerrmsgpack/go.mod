@@ -0,0 +1,15 @@
+module github.com/n-r-w/nerr/nerrmsgpack
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+)
+
+require (
+	github.com/n-r-w/eno v1.0.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
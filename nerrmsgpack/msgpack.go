@@ -0,0 +1,37 @@
+// Package nerrmsgpack - сериализация цепочки ошибок nerr в MessagePack с теми же гарантиями, что и JSON-представление
+package nerrmsgpack
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/n-r-w/nerr"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Marshal сериализует цепочку ошибок (см. nerr.Envelope)
+func Marshal(err error) ([]byte, error) {
+	return msgpack.Marshal(nerr.ToEnvelope(err))
+}
+
+// Unmarshal восстанавливает цепочку ошибок с ограничениями nerr.DefaultEnvelopeLimits
+func Unmarshal(data []byte) (*nerr.Error, error) {
+	return UnmarshalLimits(data, nerr.DefaultEnvelopeLimits)
+}
+
+// UnmarshalLimits восстанавливает цепочку ошибок с заданными ограничениями
+func UnmarshalLimits(data []byte, limits nerr.EnvelopeLimits) (*nerr.Error, error) {
+	if limits.MaxSize > 0 && len(data) > limits.MaxSize {
+		return nil, nerr.ErrEnvelopeTooLarge
+	}
+
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields(true)
+
+	var env *nerr.Envelope
+	if err := dec.Decode(&env); err != nil {
+		return nil, fmt.Errorf("%w: %v", nerr.ErrEnvelopeInvalid, err)
+	}
+
+	return nerr.ErrorFromEnvelope(env, limits)
+}
@@ -0,0 +1,12 @@
+module github.com/n-r-w/nerr/nerrmysql
+
+go 1.19
+
+require (
+	github.com/go-sql-driver/mysql v1.7.0
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,70 @@
+// Package nerrmysql - классификация ошибок github.com/go-sql-driver/mysql.
+// Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable для ошибок MySQL
+package nerrmysql
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/n-r-w/nerr"
+)
+
+// Номера ошибок сервера MySQL
+const (
+	ErDupEntry           = 1062
+	ErLockWaitTimeout    = 1205
+	ErLockDeadlock       = 1213
+	ErRowIsReferenced    = 1451
+	ErNoReferencedRow    = 1452
+	ErCheckConstraintBad = 3819
+)
+
+func init() {
+	nerr.MapError(IsDuplicateEntry, nerr.ErrConflict)
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// Number возвращает номер ошибки MySQL из любого места цепочки или 0
+func Number(err error) uint16 {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return myErr.Number
+	}
+	return 0
+}
+
+// SqlState возвращает SQLSTATE ошибки MySQL из любого места цепочки
+func SqlState(err error) string {
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return string(myErr.SQLState[:])
+	}
+	return ""
+}
+
+// IsDuplicateEntry проверяет нарушение уникальности (1062)
+func IsDuplicateEntry(err error) bool {
+	return Number(err) == ErDupEntry
+}
+
+// IsForeignKeyViolation проверяет нарушение внешнего ключа (1451, 1452)
+func IsForeignKeyViolation(err error) bool {
+	n := Number(err)
+	return n == ErRowIsReferenced || n == ErNoReferencedRow
+}
+
+// IsCheckViolation проверяет нарушение ограничения CHECK (3819)
+func IsCheckViolation(err error) bool {
+	return Number(err) == ErCheckConstraintBad
+}
+
+// IsDeadlock проверяет взаимоблокировку (1213)
+func IsDeadlock(err error) bool {
+	return Number(err) == ErLockDeadlock
+}
+
+// IsRetryable проверяет, что транзакцию можно повторить: взаимоблокировка (1213) или истекло ожидание блокировки (1205)
+func IsRetryable(err error) bool {
+	n := Number(err)
+	return n == ErLockDeadlock || n == ErLockWaitTimeout || errors.Is(err, mysql.ErrInvalidConn)
+}
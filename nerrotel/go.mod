@@ -0,0 +1,20 @@
+module github.com/n-r-w/nerr/nerrotel
+
+// go.opentelemetry.io/otel v1.28 требует Go 1.21, остальные модули nerr - Go 1.19
+go 1.21
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/log v0.4.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
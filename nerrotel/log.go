@@ -0,0 +1,86 @@
+package nerrotel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/n-r-w/nerr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// LoggerName - имя журнала OpenTelemetry, используемого LogReporter по умолчанию
+const LoggerName = "github.com/n-r-w/nerr/nerrotel"
+
+// LogReporter передает ошибки nerr в журнал OpenTelemetry (для доставки через OTel collector).
+// Связь с трассой берется из контекста Report:
+//
+//	nerr.AddReporter(nerrotel.LogReporter{})
+type LogReporter struct {
+	// Logger - журнал для записей. Если не задан, используется журнал LoggerName глобального LoggerProvider
+	Logger log.Logger
+	// Severity - уровень записей. По умолчанию log.SeverityError
+	Severity log.Severity
+}
+
+func (r LogReporter) Report(ctx context.Context, e *nerr.Error) {
+	logger := r.Logger
+	if logger == nil {
+		logger = global.GetLoggerProvider().Logger(LoggerName)
+	}
+
+	severity := r.Severity
+	if severity == log.SeverityUndefined {
+		severity = log.SeverityError
+	}
+
+	logger.Emit(ctx, LogRecord(e, severity))
+}
+
+// LogRecord возвращает запись журнала OpenTelemetry с текстом ошибки и атрибутами Attributes
+func LogRecord(err error, severity log.Severity) log.Record {
+	var rec log.Record
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetSeverity(severity)
+
+	if err == nil {
+		return rec
+	}
+
+	var e *nerr.Error
+	if errors.As(err, &e) && !e.Time.IsZero() {
+		rec.SetTimestamp(e.Time)
+	}
+
+	rec.SetBody(log.StringValue(err.Error()))
+	for _, kv := range Attributes(err) {
+		rec.AddAttributes(logKeyValue(kv))
+	}
+
+	return rec
+}
+
+// logKeyValue преобразует атрибут трассировки в атрибут журнала
+func logKeyValue(kv attribute.KeyValue) log.KeyValue {
+	key := string(kv.Key)
+
+	switch kv.Value.Type() {
+	case attribute.BOOL:
+		return log.Bool(key, kv.Value.AsBool())
+	case attribute.INT64:
+		return log.Int64(key, kv.Value.AsInt64())
+	case attribute.FLOAT64:
+		return log.Float64(key, kv.Value.AsFloat64())
+	case attribute.STRINGSLICE:
+		values := kv.Value.AsStringSlice()
+		items := make([]log.Value, 0, len(values))
+		for _, v := range values {
+			items = append(items, log.StringValue(v))
+		}
+		return log.Slice(key, items...)
+	default:
+		return log.String(key, kv.Value.Emit())
+	}
+}
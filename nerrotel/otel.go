@@ -0,0 +1,111 @@
+// Package nerrotel - запись ошибок nerr в спаны и журнал OpenTelemetry.
+// Импорт пакета также включает добавление trace_id и span_id в ошибки, созданные через nerr.NewCtx
+package nerrotel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Поля, которые NewCtx добавляет к ошибке при наличии спана в контексте
+const (
+	FieldTraceID = "trace_id"
+	FieldSpanID  = "span_id"
+)
+
+const (
+	AttrCode    = attribute.Key("nerr.code")
+	AttrOps     = attribute.Key("nerr.ops")
+	AttrTopOp   = attribute.Key("nerr.top_op")
+	AttrMessage = attribute.Key("exception.message")
+	AttrType    = attribute.Key("exception.type")
+	AttrStack   = attribute.Key("exception.stacktrace")
+)
+
+func init() {
+	nerr.RegisterContextExtractor(SpanFields)
+}
+
+// SpanFields возвращает идентификаторы трассы и спана из контекста
+func SpanFields(ctx context.Context) map[string]any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return map[string]any{
+		FieldTraceID: sc.TraceID().String(),
+		FieldSpanID:  sc.SpanID().String(),
+	}
+}
+
+// RecordError записывает ошибку в активный спан контекста с атрибутами nerr (код, цепочка op, трасса)
+// и выставляет статус спана
+func RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.RecordError(err, trace.WithAttributes(Attributes(err)...))
+	span.SetStatus(codes.Error, Status(err))
+}
+
+// Attributes возвращает атрибуты ошибки в соответствии с семантическими соглашениями OpenTelemetry
+func Attributes(err error) []attribute.KeyValue {
+	if err == nil {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{
+		AttrType.String(fmt.Sprintf("%T", rootCause(err))),
+		AttrMessage.String(err.Error()),
+		AttrOps.StringSlice(nerr.Ops(err)),
+	}
+
+	if code := nerr.OutermostCode(err); code != 0 {
+		attrs = append(attrs, AttrCode.Int(code))
+	}
+
+	if op := nerr.InnermostOp(err); len(op) > 0 {
+		attrs = append(attrs, AttrTopOp.String(op))
+	}
+
+	if tr := nerr.Trace(err); len(tr) > 0 {
+		attrs = append(attrs, AttrStack.String(strings.Join(tr, "\n")))
+	}
+
+	return attrs
+}
+
+// Status возвращает описание статуса спана по коду ошибки
+func Status(err error) string {
+	op := nerr.InnermostOp(err)
+	if code := nerr.OutermostCode(err); code != 0 {
+		return fmt.Sprintf("code %d: %s", code, op)
+	}
+	if len(op) > 0 {
+		return op
+	}
+	return err.Error()
+}
+
+func rootCause(err error) error {
+	for {
+		e, ok := err.(*nerr.Error)
+		if !ok || e.Err == nil {
+			return err
+		}
+		err = e.Err
+	}
+}
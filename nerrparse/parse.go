@@ -0,0 +1,192 @@
+// Package nerrparse восстанавливает структуру цепочки ошибок nerr из текста Error() и Trace(), сохраненного в журналах.
+// Текст не содержит всех сведений об ошибке, поэтому разбор выполняется по возможности: нераспознанные части
+// сохраняются как текст
+package nerrparse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Level - уровень цепочки, восстановленный из текста
+type Level struct {
+	Op string `json:"op,omitempty"`
+	// Repeats - количество подряд идущих уровней с той же операцией, свернутых в "op (xN)"
+	Repeats   int    `json:"repeats,omitempty"`
+	Code      int    `json:"code,omitempty"`
+	Place     string `json:"place,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Goroutine uint64 `json:"goroutine,omitempty"`
+	Labels    string `json:"labels,omitempty"`
+	Fields    string `json:"fields,omitempty"`
+	// Branch - номер ошибки в MultiError для строк Trace вида "[N] ...". -1 - вне MultiError
+	Branch int `json:"branch"`
+}
+
+// Chain - цепочка, восстановленная из текста Error()
+type Chain struct {
+	// Levels - уровни от внешнего к внутреннему. Place у них не заполняется: в тексте есть только источник.
+	// Code - первый ненулевой код, начиная с уровня (nerr.OutermostCode), как в тексте Error()
+	Levels []Level `json:"levels"`
+	// Source - строка трассы места возникновения (последний уровень Trace), если она есть в тексте
+	Source *Level `json:"source,omitempty"`
+	// Cause - текст сторонней ошибки в конце цепочки
+	Cause string `json:"cause,omitempty"`
+	// More - количество уровней, пропущенных из-за Config.MaxChainDepth
+	More int `json:"more,omitempty"`
+}
+
+const (
+	levelSep  = " => "
+	sourceSep = ", source: "
+	codeSep   = ", code: "
+	traceSep  = "; "
+)
+
+// ParseError разбирает текст Error() со стандартным форматом (nerr.FormatDefault)
+func ParseError(text string) Chain {
+	var c Chain
+
+	parts := strings.Split(text, levelSep)
+	for i, part := range parts {
+		if more, ok := parseMore(part); ok {
+			c.More = more
+			break
+		}
+
+		level, source, ok := parseHead(part)
+		if !ok {
+			c.Cause = strings.Join(parts[i:], levelSep)
+			break
+		}
+
+		c.Levels = append(c.Levels, level)
+		if c.Source == nil && len(source) > 0 {
+			s := ParseTraceLine(source)
+			c.Source = &s
+		}
+	}
+
+	return c
+}
+
+// ParseTrace разбирает строки Trace()
+func ParseTrace(lines []string) []Level {
+	res := make([]Level, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			res = append(res, ParseTraceLine(line))
+		}
+	}
+	return res
+}
+
+// ParseTraceLine разбирает строку Trace(): "place; op: ...; code: ...; fields: ..."
+func ParseTraceLine(line string) Level {
+	level := Level{Branch: -1}
+
+	if strings.HasPrefix(line, "[") {
+		if end := strings.Index(line, "] "); end > 0 {
+			if n, err := strconv.Atoi(line[1:end]); err == nil {
+				level.Branch = n
+				line = line[end+2:]
+			}
+		}
+	}
+
+	// поля выводятся последними, и их значения могут содержать разделитель
+	if i := strings.Index(line, traceSep+"fields: "); i >= 0 {
+		level.Fields = line[i+len(traceSep+"fields: "):]
+		line = line[:i]
+	}
+
+	parts := strings.Split(line, traceSep)
+	for i, part := range parts {
+		key, value, ok := strings.Cut(part, ": ")
+		if !ok || i == 0 {
+			if i == 0 {
+				level.Place = part
+			}
+			continue
+		}
+
+		switch key {
+		case "op":
+			level.Op = value
+		case "code":
+			level.Code, _ = strconv.Atoi(value)
+		case "time":
+			level.Time = value
+		case "goroutine":
+			level.Goroutine, _ = strconv.ParseUint(value, 10, 64)
+		case "labels":
+			level.Labels = value
+		}
+	}
+
+	return level
+}
+
+// parseHead разбирает заголовок уровня: "op: X (xN), code: N, source: <строка трассы>"
+func parseHead(s string) (level Level, source string, ok bool) {
+	level.Branch = -1
+
+	if !strings.HasPrefix(s, "op: ") && !strings.HasPrefix(s, "code: ") && !strings.HasPrefix(s, "source: ") {
+		return level, "", false
+	}
+
+	if strings.HasPrefix(s, "source: ") {
+		return level, strings.TrimPrefix(s, "source: "), true
+	}
+	if i := strings.Index(s, sourceSep); i >= 0 {
+		source = s[i+len(sourceSep):]
+		s = s[:i]
+	}
+
+	if strings.HasPrefix(s, "code: ") {
+		level.Code, _ = strconv.Atoi(strings.TrimPrefix(s, "code: "))
+		return level, source, true
+	}
+
+	s = strings.TrimPrefix(s, "op: ")
+	if i := strings.LastIndex(s, codeSep); i >= 0 {
+		if code, err := strconv.Atoi(s[i+len(codeSep):]); err == nil {
+			level.Code = code
+			s = s[:i]
+		}
+	}
+
+	level.Op, level.Repeats = parseRepeats(s)
+	return level, source, true
+}
+
+// parseRepeats отделяет от операции отметку "(xN)" о свернутых уровнях
+func parseRepeats(op string) (string, int) {
+	if !strings.HasSuffix(op, ")") {
+		return op, 0
+	}
+
+	i := strings.LastIndex(op, " (x")
+	if i < 0 {
+		return op, 0
+	}
+
+	n, err := strconv.Atoi(op[i+3 : len(op)-1])
+	if err != nil || n < 2 {
+		return op, 0
+	}
+	return op[:i], n
+}
+
+// parseMore разбирает отметку о пропущенных уровнях "… N more"
+func parseMore(s string) (int, bool) {
+	if !strings.HasPrefix(s, "… ") || !strings.HasSuffix(s, " more") {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(s, "… "), " more"))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
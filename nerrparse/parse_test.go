@@ -0,0 +1,75 @@
+package nerrparse_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrparse"
+)
+
+func TestParseError(t *testing.T) {
+	err := nerr.New("service", nerr.New("retry", nerr.New("retry", nerr.New("repo.load", 5001, errors.New("boom => bad")))))
+
+	c := nerrparse.ParseError(err.Error())
+
+	want := []nerrparse.Level{
+		{Op: "service", Code: 5001, Branch: -1},
+		{Op: "retry", Repeats: 2, Code: 5001, Branch: -1},
+		{Op: "repo.load", Code: 5001, Branch: -1},
+	}
+	if !reflect.DeepEqual(c.Levels, want) {
+		t.Fatalf("Levels = %+v, want %+v", c.Levels, want)
+	}
+	if c.Cause != "boom => bad" {
+		t.Fatalf("Cause = %q", c.Cause)
+	}
+	if c.Source == nil || !strings.Contains(c.Source.Place, "parse_test.go:") || c.Source.Op != "repo.load" {
+		t.Fatalf("Source = %+v", c.Source)
+	}
+
+	var ops []string
+	for _, l := range nerrparse.ParseTrace(nerr.Trace(err)) {
+		ops = append(ops, l.Op)
+	}
+	if want := []string{"service", "retry", "retry", "repo.load"}; !reflect.DeepEqual(ops, want) {
+		t.Fatalf("trace ops = %v, want %v", ops, want)
+	}
+}
+
+func TestParseErrorMore(t *testing.T) {
+	nerr.Configure(nerr.WithMaxChainDepth(2))
+	defer nerr.Configure(nerr.WithMaxChainDepth(0))
+
+	err := nerr.New("a", nerr.New("b", nerr.New("c", nerr.New("d", errors.New("boom")))))
+
+	c := nerrparse.ParseError(err.Error())
+	if len(c.Levels) != 2 || c.Levels[0].Op != "a" || c.Levels[1].Op != "b" || c.More != 2 {
+		t.Fatalf("ParseError(%q) = %+v", err.Error(), c)
+	}
+}
+
+func TestParseErrorForeign(t *testing.T) {
+	c := nerrparse.ParseError("connection refused")
+	if len(c.Levels) != 0 || c.Cause != "connection refused" {
+		t.Fatalf("ParseError() = %+v", c)
+	}
+}
+
+func TestParseTrace(t *testing.T) {
+	lines := []string{
+		"main.go:10; op: service; code: 9001",
+		"",
+		"[1] repo.go:20; op: repo.load; goroutine: 7; labels: path=/a; fields: id=7; note=a; b",
+	}
+
+	want := []nerrparse.Level{
+		{Place: "main.go:10", Op: "service", Code: 9001, Branch: -1},
+		{Place: "repo.go:20", Op: "repo.load", Goroutine: 7, Labels: "path=/a", Fields: "id=7; note=a; b", Branch: 1},
+	}
+	if got := nerrparse.ParseTrace(lines); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseTrace() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+// Package nerrpb - представление цепочки ошибок nerr в protobuf для передачи в деталях gRPC статуса или в событиях
+package nerrpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative nerr.proto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/n-r-w/nerr"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto преобразует цепочку ошибок в protobuf текущей версии формата (nerr.EnvelopeVersion).
+// Сторонние ошибки представлены текстом и ошибками проверки полей
+func ToProto(err error) *Error {
+	res := toProto(err)
+	if res != nil {
+		res.Version = nerr.EnvelopeVersion
+	}
+	return res
+}
+
+func toProto(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*nerr.Error)
+	if !ok {
+		res := &Error{Message: err.Error()}
+		if fields := nerr.ValidationFields(err); len(fields) > 0 {
+			res.Validation = make(map[string]*ValidationMessages, len(fields))
+			for field, messages := range fields {
+				res.Validation[field] = &ValidationMessages{Messages: messages}
+			}
+		}
+		return res
+	}
+
+	res := &Error{
+		Op:        e.Operation(),
+		Code:      int64(e.Code),
+		Place:     e.Place,
+		Goroutine: e.Goroutine,
+		Labels:    e.Labels,
+		Service:   e.Service(),
+		Err:       toProto(e.Err),
+	}
+
+	for _, f := range e.Stack {
+		res.Stack = append(res.Stack, &Frame{Function: f.Function, File: f.File, Line: int64(f.Line)})
+	}
+
+	if !e.Time.IsZero() {
+		res.Time = timestamppb.New(e.Time)
+	}
+
+	if len(e.Fields) > 0 {
+		res.Fields = make(map[string]*structpb.Value, len(e.Fields))
+		for k, v := range e.Fields {
+			res.Fields[k] = toValue(v)
+		}
+	}
+
+	return res
+}
+
+// FromProto восстанавливает цепочку ошибок. Сторонние ошибки восстанавливаются как текст,
+// ошибки проверки полей - как nerr.ValidationErrors. Сообщения старых версий (без version) разбираются так же,
+// неизвестные поля более новых версий остаются в p и не переносятся в цепочку.
+// Уровни *nerr.Error отмечаются как полученные из сервиса Error.Service (см. nerr.Error.Remote)
+func FromProto(p *Error) error {
+	if p == nil {
+		return nil
+	}
+
+	if len(p.Message) > 0 || len(p.Validation) > 0 {
+		if len(p.Validation) > 0 {
+			return validationErrors(p.Validation)
+		}
+		return errors.New(p.Message)
+	}
+
+	res := &nerr.Error{
+		Op:        p.Op,
+		Code:      int(p.Code),
+		Place:     p.Place,
+		Goroutine: p.Goroutine,
+		Labels:    p.Labels,
+		Err:       FromProto(p.Err),
+	}
+
+	for _, f := range p.Stack {
+		res.Stack = append(res.Stack, nerr.Frame{Function: f.Function, File: f.File, Line: int(f.Line)})
+	}
+
+	if p.Time != nil {
+		res.Time = p.Time.AsTime()
+	}
+
+	if len(p.Fields) > 0 {
+		res.Fields = make(map[string]any, len(p.Fields))
+		for k, v := range p.Fields {
+			res.Fields[k] = v.AsInterface()
+		}
+	}
+
+	return nerr.WithRemote(res, p.Service)
+}
+
+// toValue преобразует значение поля. Типы, которые не поддерживает structpb, передаются текстом
+func toValue(v any) *structpb.Value {
+	if res, err := structpb.NewValue(v); err == nil {
+		return res
+	}
+	return structpb.NewStringValue(fmt.Sprint(v))
+}
+
+func validationErrors(m map[string]*ValidationMessages) nerr.ValidationErrors {
+	fields := make([]string, 0, len(m))
+	for f := range m {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	var res nerr.ValidationErrors
+	for _, f := range fields {
+		for _, msg := range m[f].GetMessages() {
+			res.Add(f, "", msg)
+		}
+	}
+	return res
+}
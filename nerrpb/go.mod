@@ -0,0 +1,12 @@
+module github.com/n-r-w/nerr/nerrpb
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	google.golang.org/protobuf v1.28.1
+)
+
+require github.com/n-r-w/eno v1.0.1 // indirect
+
+replace github.com/n-r-w/nerr => ..
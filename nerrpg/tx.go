@@ -0,0 +1,64 @@
+// Package nerrpg - выполнение транзакций database/sql с классификацией ошибок начала, фиксации и отката.
+// Подходит для любого драйвера, ошибки PostgreSQL распознаются правилами nerr.IsRetryableSQL
+package nerrpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/n-r-w/nerr"
+)
+
+// FieldRollbackError - поле с ошибкой отката (*nerr.Error с кодом nerr.ErrTxRollback),
+// возникшей после ошибки функции транзакции
+const FieldRollbackError = "tx.rollback_error"
+
+// TxBeginner - источник транзакций, например *sql.DB или *sql.Conn
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// WithTx выполняет fn в транзакции: фиксирует ее, если fn вернула nil, иначе откатывает.
+// Ошибки начала и фиксации возвращаются с кодами nerr.ErrTxBegin и nerr.ErrTxCommit, ошибка fn - без изменений.
+// Если откат не удался, его ошибка прикрепляется к ошибке fn как вторичная в поле FieldRollbackError.
+// Повторяемость (конфликт сериализации, взаимоблокировка, сбой соединения) определяет nerr.IsRetryable
+func WithTx(ctx context.Context, db TxBeginner, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nerr.New(nerr.Skip(1), "tx.begin", nerr.ErrTxBegin, err)
+	}
+
+	panicked := true
+	defer func() {
+		if panicked {
+			_ = tx.Rollback()
+		}
+	}()
+
+	err = fn(tx)
+	panicked = false
+
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return withRollbackError(err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nerr.New(nerr.Skip(1), "tx.commit", nerr.ErrTxCommit, err)
+	}
+	return nil
+}
+
+// withRollbackError прикрепляет к ошибке err ошибку отката, не меняя код и операции err
+func withRollbackError(err, rbErr error) error {
+	secondary := &nerr.Error{Op: "tx.rollback", Code: nerr.ErrTxRollback, Err: rbErr}
+
+	if e, ok := err.(*nerr.Error); ok {
+		return e.WithFields(map[string]any{FieldRollbackError: secondary})
+	}
+
+	return nerr.New(nerr.Skip(2), err, map[string]any{FieldRollbackError: secondary})
+}
@@ -0,0 +1,136 @@
+package nerrpg_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrpg"
+)
+
+// fakeDB - драйвер database/sql, возвращающий заданные ошибки начала, фиксации и отката
+type fakeDB struct {
+	beginErr, commitErr, rollbackErr error
+
+	committed, rolledBack bool
+}
+
+func (d *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	if c.db.beginErr != nil {
+		return nil, c.db.beginErr
+	}
+	return fakeTx(c), nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (t fakeTx) Commit() error {
+	t.db.committed = true
+	return t.db.commitErr
+}
+
+func (t fakeTx) Rollback() error {
+	t.db.rolledBack = true
+	return t.db.rollbackErr
+}
+
+func TestWithTx(t *testing.T) {
+	failure := errors.New("failure")
+	fnErr := nerr.New("repo.save", nerr.ErrConflict)
+
+	tests := []struct {
+		name           string
+		db             fakeDB
+		fnErr          error
+		wantCode       int
+		wantSame       bool
+		wantRollbackOK bool
+		committed      bool
+		rolledBack     bool
+	}{
+		{name: "commit", committed: true},
+		{name: "begin error", db: fakeDB{beginErr: failure}, wantCode: nerr.ErrTxBegin},
+		{name: "commit error", db: fakeDB{commitErr: failure}, wantCode: nerr.ErrTxCommit, committed: true},
+		{name: "rollback", fnErr: fnErr, wantCode: nerr.ErrConflict, wantSame: true, rolledBack: true},
+		{name: "rollback error", db: fakeDB{rollbackErr: failure}, fnErr: fnErr, wantCode: nerr.ErrConflict,
+			wantRollbackOK: true, rolledBack: true},
+		{name: "rollback error foreign", db: fakeDB{rollbackErr: failure}, fnErr: failure,
+			wantRollbackOK: true, rolledBack: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := tt.db
+			db := sql.OpenDB(&fake)
+			defer db.Close()
+
+			err := nerrpg.WithTx(context.Background(), db, func(*sql.Tx) error { return tt.fnErr })
+
+			if tt.wantCode == 0 && tt.fnErr == nil {
+				if err != nil {
+					t.Fatalf("WithTx() = %v", err)
+				}
+			} else if tt.wantCode != 0 && !nerr.IsCode(err, tt.wantCode) {
+				t.Fatalf("code %d not found in %v", tt.wantCode, err)
+			}
+			if tt.wantSame && err != tt.fnErr {
+				t.Fatalf("WithTx() = %v, want fn error unchanged", err)
+			}
+			// ошибка отката прикрепляется к копии *nerr.Error, сторонняя ошибка fn оборачивается
+			if _, ok := tt.fnErr.(*nerr.Error); ok {
+				if !reflect.DeepEqual(nerr.Ops(err), nerr.Ops(tt.fnErr)) {
+					t.Fatalf("ops = %v, want %v", nerr.Ops(err), nerr.Ops(tt.fnErr))
+				}
+			} else if tt.fnErr != nil && !errors.Is(err, tt.fnErr) {
+				t.Fatalf("fn error lost: %v", err)
+			}
+
+			rb, ok := nerr.Field(err, nerrpg.FieldRollbackError)
+			if ok != tt.wantRollbackOK {
+				t.Fatalf("%s present = %v, want %v", nerrpg.FieldRollbackError, ok, tt.wantRollbackOK)
+			}
+			if ok {
+				rbErr, _ := rb.(error)
+				if !nerr.IsCode(rbErr, nerr.ErrTxRollback) || !errors.Is(rbErr, tt.db.rollbackErr) {
+					t.Fatalf("%s = %v", nerrpg.FieldRollbackError, rb)
+				}
+				if nerr.IsCode(err, nerr.ErrTxRollback) {
+					t.Fatal("rollback code leaked into the returned chain")
+				}
+			}
+
+			if fake.committed != tt.committed || fake.rolledBack != tt.rolledBack {
+				t.Fatalf("committed = %v, rolled back = %v", fake.committed, fake.rolledBack)
+			}
+		})
+	}
+}
+
+func TestWithTxPanic(t *testing.T) {
+	var fake fakeDB
+	db := sql.OpenDB(&fake)
+	defer db.Close()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("panic not propagated")
+		}
+		if !fake.rolledBack {
+			t.Fatal("transaction not rolled back")
+		}
+	}()
+
+	_ = nerrpg.WithTx(context.Background(), db, func(*sql.Tx) error { panic("boom") })
+}
@@ -0,0 +1,67 @@
+// Package nerrprom - метрики Prometheus для ошибок nerr
+package nerrprom
+
+import (
+	"context"
+
+	"github.com/n-r-w/nerr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultSeverity - уровень ошибки, если Counter.Severity не задан
+const DefaultSeverity = "error"
+
+// Counter - счетчик ошибок с метками code, op и severity. Реализует prometheus.Collector
+type Counter struct {
+	vec *prometheus.CounterVec
+
+	// Severity определяет значение метки severity. Если не задан, используется DefaultSeverity
+	Severity func(err error) string
+	// Labels ограничивает значения меток code и op. По умолчанию операции нормализуются через nerr.NormalizeOp
+	Labels nerr.MetricLabels
+}
+
+// NewCounter создает счетчик <namespace>_errors_total
+func NewCounter(namespace string) *Counter {
+	return &Counter{
+		vec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of errors by code, operation and severity.",
+		}, []string{"code", "op", "severity"}),
+	}
+}
+
+// Install подписывает счетчик на создание всех ошибок nerr
+func (c *Counter) Install() {
+	nerr.AddHook(func(e *nerr.Error) {
+		c.Observe(e)
+	})
+}
+
+// Report позволяет использовать счетчик как nerr.Reporter
+func (c *Counter) Report(_ context.Context, e *nerr.Error) {
+	c.Observe(e)
+}
+
+// Observe учитывает ошибку в счетчике
+func (c *Counter) Observe(err error) {
+	if err == nil {
+		return
+	}
+
+	severity := DefaultSeverity
+	if c.Severity != nil {
+		severity = c.Severity(err)
+	}
+
+	c.vec.WithLabelValues(c.Labels.Code(err), c.Labels.Op(err), severity).Inc()
+}
+
+func (c *Counter) Describe(ch chan<- *prometheus.Desc) {
+	c.vec.Describe(ch)
+}
+
+func (c *Counter) Collect(ch chan<- prometheus.Metric) {
+	c.vec.Collect(ch)
+}
@@ -0,0 +1,16 @@
+module github.com/n-r-w/nerr/nerrredis
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/redis/go-redis/v9 v9.0.2
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
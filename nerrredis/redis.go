@@ -0,0 +1,62 @@
+// Package nerrredis - классификация ошибок github.com/redis/go-redis.
+// Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable для ошибок Redis
+package nerrredis
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+	"github.com/redis/go-redis/v9"
+)
+
+// Префиксы ответов сервера, после которых команду можно повторить
+var retryablePrefixes = []string{"LOADING ", "READONLY ", "CLUSTERDOWN ", "TRYAGAIN ", "MASTERDOWN "}
+
+func init() {
+	nerr.MapError(IsNotFound, nerr.ErrNotFound)
+	nerr.MapError(IsTimeout, nerr.ErrTimeout)
+	nerr.MapError(IsConnection, nerr.ErrUnavailable)
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// IsNotFound проверяет отсутствие ключа (redis.Nil)
+func IsNotFound(err error) bool {
+	return errors.Is(err, redis.Nil)
+}
+
+// IsTimeout проверяет истечение времени ожидания сетевой операции
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsConnection проверяет ошибку соединения с сервером
+func IsConnection(err error) bool {
+	if errors.Is(err, redis.ErrClosed) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// IsRetryable проверяет, что команду можно повторить: сбой соединения, таймаут или временное состояние сервера
+func IsRetryable(err error) bool {
+	if IsConnection(err) || IsTimeout(err) {
+		return true
+	}
+
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		msg := redisErr.Error()
+		for _, p := range retryablePrefixes {
+			if strings.HasPrefix(msg, p) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
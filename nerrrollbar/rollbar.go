@@ -0,0 +1,91 @@
+// Package nerrrollbar - отправка ошибок nerr в Rollbar
+package nerrrollbar
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/n-r-w/nerr"
+	"github.com/rollbar/rollbar-go"
+)
+
+// DefaultSeverity - уровень ошибки, если Reporter.Severity не задан
+const DefaultSeverity = rollbar.ERR
+
+// Reporter отправляет ошибки в Rollbar. Кадры nerr становятся стеком ошибки, код, операции, поля и nerr.Fingerprint -
+// дополнительными данными. Если Client не задан, используется стандартный клиент пакета rollbar
+type Reporter struct {
+	Client *rollbar.Client
+
+	// Severity определяет уровень ошибки (rollbar.CRIT, rollbar.ERR, rollbar.WARN и т.д.).
+	// Если не задан, используется DefaultSeverity
+	Severity func(err error) string
+}
+
+func (r Reporter) Report(ctx context.Context, e *nerr.Error) {
+	severity := DefaultSeverity
+	if r.Severity != nil {
+		severity = r.Severity(e)
+	}
+
+	if r.Client != nil {
+		r.Client.ErrorWithStackSkipWithExtrasAndContext(ctx, severity, withFrames{e}, 0, Extras(e))
+		return
+	}
+	rollbar.ErrorWithStackSkipWithExtrasAndContext(ctx, severity, withFrames{e}, 0, Extras(e))
+}
+
+// Extras возвращает дополнительные данные Rollbar: код, операции, поля и nerr.Fingerprint
+func Extras(err error) map[string]any {
+	extras := map[string]any{
+		"nerr.fingerprint": nerr.Fingerprint(err),
+	}
+	if code := nerr.OutermostCode(err); code != 0 {
+		extras["nerr.code"] = code
+	}
+	if ops := nerr.Ops(err); len(ops) > 0 {
+		extras["nerr.ops"] = ops
+	}
+	for k, v := range nerr.AllFields(err) {
+		extras[k] = v
+	}
+	return extras
+}
+
+// withFrames передает Rollbar кадры nerr (rollbar.Stacker) вместо стека места вызова Report
+type withFrames struct {
+	err *nerr.Error
+}
+
+func (w withFrames) Error() string {
+	return w.err.Error()
+}
+
+func (w withFrames) Unwrap() error {
+	return w.err
+}
+
+// Stack возвращает кадры цепочки от места возникновения внутренней ошибки к внешним уровням
+func (w withFrames) Stack() []runtime.Frame {
+	var levels [][]nerr.Frame
+	for cur := error(w.err); cur != nil; {
+		e, ok := cur.(*nerr.Error)
+		if !ok {
+			break
+		}
+		levels = append(levels, e.Frames())
+		cur = e.Err
+	}
+
+	var frames []runtime.Frame
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, f := range levels[i] {
+			frames = append(frames, runtime.Frame{
+				Function: f.Function,
+				File:     f.File,
+				Line:     f.Line,
+			})
+		}
+	}
+	return frames
+}
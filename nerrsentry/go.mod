@@ -0,0 +1,16 @@
+module github.com/n-r-w/nerr/nerrsentry
+
+go 1.19
+
+require (
+	github.com/getsentry/sentry-go v0.18.0
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require (
+	github.com/n-r-w/eno v1.0.1 // indirect
+	golang.org/x/sys v0.0.0-20220928140112-f11e5e49a4ec // indirect
+	golang.org/x/text v0.3.7 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,128 @@
+// Package nerrsentry - отправка ошибок nerr в Sentry
+package nerrsentry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/n-r-w/nerr"
+)
+
+// Теги Sentry
+const (
+	// TagCode - код ошибки
+	TagCode = "nerr.code"
+	// TagRemoteService - сервис, в котором создан внутренний уровень, восстановленный из представления другого
+	// компонента (см. nerr.Error.Remote)
+	TagRemoteService = "nerr.remote_service"
+)
+
+// Event преобразует ошибку в событие Sentry: кадры nerr становятся стеком исключения, код - тегом,
+// поля - дополнительными данными, а nerr.Fingerprint - ключом группировки.
+// Кадры уровней из других компонентов отмечаются как не относящиеся к приложению, с именем сервиса в Package
+func Event(err error) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelError
+	if err == nil {
+		return event
+	}
+
+	event.Message = err.Error()
+	event.Fingerprint = []string{nerr.Fingerprint(err)}
+
+	if code := nerr.OutermostCode(err); code != 0 {
+		event.Tags[TagCode] = strconv.Itoa(code)
+	}
+
+	var root error
+	var frames []sentry.Frame
+	for cur := err; cur != nil; {
+		e, ok := cur.(*nerr.Error)
+		if !ok {
+			root = cur
+			break
+		}
+
+		for k, v := range e.Fields {
+			event.Extra[k] = v
+		}
+
+		service, remote := e.Remote()
+		if remote && len(service) > 0 {
+			event.Tags[TagRemoteService] = service
+		}
+
+		// Sentry ожидает кадры от внешнего вызова к месту ошибки
+		levelFrames := e.Frames()
+		for i := len(levelFrames) - 1; i >= 0; i-- {
+			f := frame(levelFrames[i])
+			if remote {
+				f.InApp = false
+				f.Package = "remote"
+				if len(service) > 0 {
+					f.Package = "remote " + service
+				}
+			}
+			frames = append(frames, f)
+		}
+
+		root = e
+		cur = e.Err
+	}
+
+	exception := sentry.Exception{
+		Type:  nerr.InnermostOp(err),
+		Value: err.Error(),
+	}
+	if _, ok := root.(*nerr.Error); !ok {
+		exception.Type = fmt.Sprintf("%T", root)
+	}
+	if len(frames) > 0 {
+		exception.Stacktrace = &sentry.Stacktrace{Frames: frames}
+	}
+	event.Exception = []sentry.Exception{exception}
+
+	return event
+}
+
+func frame(f nerr.Frame) sentry.Frame {
+	module, function := splitFunction(f.Function)
+
+	return sentry.Frame{
+		Function: function,
+		Module:   module,
+		AbsPath:  f.File,
+		Lineno:   f.Line,
+		InApp:    true,
+	}
+}
+
+// splitFunction разделяет полное имя функции на пакет и имя: "github.com/a/b.(*T).M" -> "github.com/a/b", "(*T).M"
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/") + 1
+	if dot := strings.Index(name[slash:], "."); dot >= 0 {
+		return name[:slash+dot], name[slash+dot+1:]
+	}
+
+	return "", name
+}
+
+// Reporter отправляет ошибки в Sentry. Если Hub не задан, используется хаб из контекста или текущий
+type Reporter struct {
+	Hub *sentry.Hub
+}
+
+func (r Reporter) Report(ctx context.Context, e *nerr.Error) {
+	hub := r.Hub
+	if hub == nil {
+		hub = sentry.GetHubFromContext(ctx)
+	}
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	hub.CaptureEvent(Event(e))
+}
@@ -0,0 +1,27 @@
+// Package mattn подключает распознавание ошибок github.com/mattn/go-sqlite3 в nerrsqlite
+package mattn
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/n-r-w/nerr/nerrsqlite"
+)
+
+func init() {
+	nerrsqlite.RegisterExtractor(extendedCode)
+}
+
+func extendedCode(err error) (int, bool) {
+	var e sqlite3.Error
+	if errors.As(err, &e) {
+		return int(e.ExtendedCode), true
+	}
+
+	var pe *sqlite3.Error
+	if errors.As(err, &pe) && pe != nil {
+		return int(pe.ExtendedCode), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,121 @@
+// Package nerrsqlite - классификация ошибок SQLite.
+// Ошибки modernc.org/sqlite распознаются без дополнительных зависимостей, для github.com/mattn/go-sqlite3
+// нужно импортировать пакет nerrsqlite/mattn. Импорт пакета регистрирует правила nerr.MapError и nerr.MarkRetryable
+package nerrsqlite
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/n-r-w/nerr"
+)
+
+// Коды результата SQLite
+const (
+	SqliteBusy       = 5
+	SqliteLocked     = 6
+	SqliteConstraint = 19
+
+	SqliteConstraintForeignKey = SqliteConstraint | 3<<8
+	SqliteConstraintNotNull    = SqliteConstraint | 5<<8
+	SqliteConstraintPrimaryKey = SqliteConstraint | 6<<8
+	SqliteConstraintUnique     = SqliteConstraint | 8<<8
+)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []func(err error) (int, bool)
+)
+
+func init() {
+	RegisterExtractor(moderncCode)
+
+	nerr.MapError(IsUniqueViolation, nerr.ErrConflict)
+	nerr.MarkRetryable(IsRetryable)
+}
+
+// RegisterExtractor регистрирует функцию, извлекающую расширенный код SQLite из ошибки драйвера. Функция получает
+// всю цепочку и ищет в ней ошибку драйвера через errors.As. Вызывать при инициализации
+func RegisterExtractor(fn func(err error) (int, bool)) {
+	extractorsMu.Lock()
+	extractors = append(extractors, fn)
+	extractorsMu.Unlock()
+}
+
+// ExtendedCode возвращает расширенный код результата SQLite из любого места цепочки или 0
+func ExtendedCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	// элементы списка не изменяются, поэтому после чтения среза блокировка не нужна
+	extractorsMu.RLock()
+	list := extractors
+	extractorsMu.RUnlock()
+
+	for _, fn := range list {
+		if code, ok := fn(err); ok {
+			return code
+		}
+	}
+	return 0
+}
+
+// Code возвращает основной код результата SQLite из любого места цепочки или 0
+func Code(err error) int {
+	return ExtendedCode(err) & 0xff
+}
+
+// IsConstraint проверяет нарушение любого ограничения (19)
+func IsConstraint(err error) bool {
+	return Code(err) == SqliteConstraint
+}
+
+// IsUniqueViolation проверяет нарушение уникальности или первичного ключа
+func IsUniqueViolation(err error) bool {
+	code := ExtendedCode(err)
+	return code == SqliteConstraintUnique || code == SqliteConstraintPrimaryKey
+}
+
+// IsForeignKeyViolation проверяет нарушение внешнего ключа
+func IsForeignKeyViolation(err error) bool {
+	return ExtendedCode(err) == SqliteConstraintForeignKey
+}
+
+// IsBusy проверяет, что база занята другим соединением (5)
+func IsBusy(err error) bool {
+	return Code(err) == SqliteBusy
+}
+
+// IsLocked проверяет, что таблица заблокирована (6)
+func IsLocked(err error) bool {
+	return Code(err) == SqliteLocked
+}
+
+// IsRetryable проверяет, что операцию можно повторить: база занята или заблокирована
+func IsRetryable(err error) bool {
+	code := Code(err)
+	return code == SqliteBusy || code == SqliteLocked
+}
+
+// moderncPkgPath - пакет драйвера modernc.org/sqlite, *Error которого имеет метод Code() int
+const moderncPkgPath = "modernc.org/sqlite"
+
+// moderncCode ищет ошибку драйвера так же, как nerr.SqlCode: первую ошибку цепочки с методом Code() int
+func moderncCode(err error) (int, bool) {
+	var e interface{ Code() int }
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+
+	t := reflect.TypeOf(e)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.PkgPath() != moderncPkgPath {
+		return 0, false
+	}
+
+	return e.Code(), true
+}
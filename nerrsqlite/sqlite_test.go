@@ -0,0 +1,87 @@
+//go:build go1.20
+
+// errors.As обходит MultiError (Unwrap() []error) начиная с Go 1.20
+
+package nerrsqlite_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrsqlite"
+)
+
+// driverError - ошибка драйвера с расширенным кодом SQLite
+type driverError struct {
+	code int
+}
+
+func (e *driverError) Error() string { return fmt.Sprintf("sqlite error %d", e.code) }
+
+func init() {
+	nerrsqlite.RegisterExtractor(func(err error) (int, bool) {
+		var e *driverError
+		if errors.As(err, &e) {
+			return e.code, true
+		}
+		return 0, false
+	})
+}
+
+func TestExtendedCode(t *testing.T) {
+	unique := &driverError{code: nerrsqlite.SqliteConstraintUnique}
+	busy := &driverError{code: nerrsqlite.SqliteBusy}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"not sqlite", errors.New("plain"), 0},
+		{"direct", unique, nerrsqlite.SqliteConstraintUnique},
+		{"nerr nested", nerr.New("service", nerr.New("repo", nerr.New("insert", unique))), nerrsqlite.SqliteConstraintUnique},
+		{"fmt over nerr", fmt.Errorf("handler: %w", nerr.New("insert", unique)), nerrsqlite.SqliteConstraintUnique},
+		{"multi", &nerr.MultiError{Errors: []error{errors.New("other"), nerr.New("insert", busy)}}, nerrsqlite.SqliteBusy},
+		{"join", nerr.New("batch", errors.Join(errors.New("other"), fmt.Errorf("row: %w", unique))), nerrsqlite.SqliteConstraintUnique},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nerrsqlite.ExtendedCode(tt.err); got != tt.want {
+				t.Fatalf("ExtendedCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	wrap := func(code int) error {
+		return &nerr.MultiError{Errors: []error{nerr.New("insert", &driverError{code: code})}}
+	}
+
+	tests := []struct {
+		name string
+		fn   func(error) bool
+		err  error
+		want bool
+	}{
+		{"unique", nerrsqlite.IsUniqueViolation, wrap(nerrsqlite.SqliteConstraintUnique), true},
+		{"primary key", nerrsqlite.IsUniqueViolation, wrap(nerrsqlite.SqliteConstraintPrimaryKey), true},
+		{"foreign key", nerrsqlite.IsForeignKeyViolation, wrap(nerrsqlite.SqliteConstraintForeignKey), true},
+		{"constraint", nerrsqlite.IsConstraint, wrap(nerrsqlite.SqliteConstraintNotNull), true},
+		{"busy retryable", nerrsqlite.IsRetryable, wrap(nerrsqlite.SqliteBusy), true},
+		{"locked retryable", nerrsqlite.IsRetryable, wrap(nerrsqlite.SqliteLocked), true},
+		{"unique not retryable", nerrsqlite.IsRetryable, wrap(nerrsqlite.SqliteConstraintUnique), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.err); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package nerrtest - проверки ошибок nerr в тестах
+package nerrtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+// AssertCode проверяет, что в цепочке есть ошибка с кодом code
+func AssertCode(t testing.TB, err error, code int) bool {
+	t.Helper()
+
+	if nerr.IsCode(err, code) {
+		return true
+	}
+
+	t.Errorf("error has no code %d\n%s", code,
+		diff(fmt.Sprintf("code: %d", code), fmt.Sprintf("code: %d", nerr.OutermostCode(err)), err))
+	return false
+}
+
+// AssertOpContains проверяет, что одна из операций цепочки содержит op
+func AssertOpContains(t testing.TB, err error, op string) bool {
+	t.Helper()
+
+	for _, o := range nerr.Ops(err) {
+		if strings.Contains(o, op) {
+			return true
+		}
+	}
+
+	t.Errorf("error has no op containing %q\n%s", op,
+		diff("op: *"+op+"*", "ops: "+strings.Join(nerr.Ops(err), " | "), err))
+	return false
+}
+
+// AssertIsRetryable проверяет, что ошибка считается временной (nerr.IsRetryable)
+func AssertIsRetryable(t testing.TB, err error) bool {
+	t.Helper()
+
+	if nerr.IsRetryable(err) {
+		return true
+	}
+
+	t.Errorf("error is not retryable\n%s", diff("retryable: true", "retryable: false", err))
+	return false
+}
+
+// AssertNotRetryable проверяет, что ошибка не считается временной
+func AssertNotRetryable(t testing.TB, err error) bool {
+	t.Helper()
+
+	if !nerr.IsRetryable(err) {
+		return true
+	}
+
+	t.Errorf("error is retryable\n%s", diff("retryable: false", "retryable: true", err))
+	return false
+}
+
+// AssertChain проверяет, что цепочка err совпадает с want по операциям, кодам, полям и текстам сторонних ошибок
+// (см. nerr.Diff)
+func AssertChain(t testing.TB, err, want error) bool {
+	t.Helper()
+
+	d := nerr.Diff(want, err)
+	if len(d) == 0 {
+		return true
+	}
+
+	t.Errorf("error chain differs\n%s", d)
+	return false
+}
+
+// diff формирует сообщение об ошибке проверки с ожидаемым и фактическим значением и всей цепочкой
+func diff(want, got string, err error) string {
+	var b strings.Builder
+
+	b.WriteString("--- want\n+++ got\n")
+	b.WriteString("- " + want + "\n")
+	b.WriteString("+ " + got + "\n")
+	b.WriteString(Chain(err))
+
+	return b.String()
+}
+
+// Chain возвращает многострочное представление цепочки ошибок для сообщений тестов
+func Chain(err error) string {
+	if err == nil {
+		return "chain: <nil>\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("chain:\n")
+
+	for i := 0; err != nil; i++ {
+		e, ok := err.(*nerr.Error)
+		if !ok {
+			fmt.Fprintf(&b, "  [%d] %T: %v\n", i, err, err)
+			break
+		}
+
+		fmt.Fprintf(&b, "  [%d] op: %q, code: %d, place: %s\n", i, e.Operation(), e.Code, e.Place)
+		err = e.Err
+	}
+
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package nerrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrtest"
+)
+
+// recorder - testing.TB, сохраняющий сообщения об ошибках проверок
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAsserts(t *testing.T) {
+	err := nerr.New("service", nerr.New("repo.load", 5001, errors.New("boom")))
+	retryable := nerr.WithRetryAfter(err, time.Second)
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB) bool
+		want   []string
+	}{
+		{"code", func(t testing.TB) bool { return nerrtest.AssertCode(t, err, 5001) }, nil},
+		{"code mismatch", func(t testing.TB) bool { return nerrtest.AssertCode(t, err, 5002) },
+			[]string{"error has no code 5002", "- code: 5002", "+ code: 5001", `[1] op: "repo.load", code: 5001`, "[2] *errors.errorString: boom"}},
+		{"op", func(t testing.TB) bool { return nerrtest.AssertOpContains(t, err, "repo") }, nil},
+		{"op mismatch", func(t testing.TB) bool { return nerrtest.AssertOpContains(t, err, "cache") },
+			[]string{`error has no op containing "cache"`, "- op: *cache*", "+ ops: service | repo.load | boom"}},
+		{"retryable", func(t testing.TB) bool { return nerrtest.AssertIsRetryable(t, retryable) }, nil},
+		{"not retryable", func(t testing.TB) bool { return nerrtest.AssertIsRetryable(t, err) },
+			[]string{"error is not retryable", "- retryable: true", "+ retryable: false"}},
+		{"permanent", func(t testing.TB) bool { return nerrtest.AssertNotRetryable(t, err) }, nil},
+		{"permanent mismatch", func(t testing.TB) bool { return nerrtest.AssertNotRetryable(t, retryable) },
+			[]string{"error is retryable", "- retryable: false", "+ retryable: true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			if ok := tt.assert(r); ok != (tt.want == nil) {
+				t.Fatalf("assert = %v, messages %q", ok, r.errors)
+			}
+			if tt.want == nil {
+				if len(r.errors) != 0 {
+					t.Fatalf("unexpected messages %q", r.errors)
+				}
+				return
+			}
+
+			if len(r.errors) != 1 {
+				t.Fatalf("messages = %q, want one", r.errors)
+			}
+			for _, s := range append(tt.want, "--- want\n+++ got\n", "chain:\n  [0] op: \"service\"") {
+				if !strings.Contains(r.errors[0], s) {
+					t.Fatalf("%q not found in\n%s", s, r.errors[0])
+				}
+			}
+		})
+	}
+}
+
+func TestChainNil(t *testing.T) {
+	if got := nerrtest.Chain(nil); got != "chain: <nil>\n" {
+		t.Fatalf("Chain(nil) = %q", got)
+	}
+}
+
+func TestAssertChain(t *testing.T) {
+	want := nerr.New("service", nerr.New("repo", 5001, errors.New("boom")))
+
+	r := &recorder{TB: t}
+	if !nerrtest.AssertChain(r, nerr.New("service", nerr.New("repo", 5001, errors.New("boom"))), want) || len(r.errors) != 0 {
+		t.Fatalf("equal chains reported %q", r.errors)
+	}
+
+	got := nerr.New("service", nerr.New("cache", nerr.New("repo", 5002, errors.New("boom"))))
+	if nerrtest.AssertChain(r, got, want) {
+		t.Fatal("AssertChain() = true for different chains")
+	}
+
+	wantMessage := `error chain differs
+--- want
++++ got
+  [0] op: service
++ [1] op: cache
+  [1] op: repo
+- [1] code: 5001
++ [2] code: 5002
+  [2] message: boom
+`
+	if len(r.errors) != 1 || r.errors[0] != wantMessage {
+		t.Fatalf("messages = %q, want %q", r.errors, wantMessage)
+	}
+}
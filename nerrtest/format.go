@@ -0,0 +1,86 @@
+package nerrtest
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+)
+
+// поля, которые зависят от машины и запуска (nerr.CaptureEnvironment)
+var volatileFields = map[string]bool{
+	"host":         true,
+	"pid":          true,
+	"vcs.revision": true,
+}
+
+// Format возвращает стабильное представление цепочки ошибок для golden-файлов: место возникновения сокращено
+// до функции и имени файла без номера строки, время, горутина и поля окружения опущены
+func Format(err error) string {
+	var b strings.Builder
+	formatChain(&b, err, "")
+	return b.String()
+}
+
+func formatChain(b *strings.Builder, err error, indent string) {
+	if err == nil {
+		b.WriteString(indent + "<nil>\n")
+		return
+	}
+
+	for err != nil {
+		switch e := err.(type) {
+		case *nerr.Error:
+			info := []string{formatPlace(e)}
+			if op := e.Operation(); len(op) > 0 {
+				info = append(info, "op: "+op)
+			}
+			if e.Code != 0 {
+				info = append(info, fmt.Sprintf("code: %d", e.Code))
+			}
+			if fields := formatStableFields(e.Fields); len(fields) > 0 {
+				info = append(info, "fields: "+fields)
+			}
+			b.WriteString(indent + strings.Join(info, "; ") + "\n")
+			err = e.Err
+
+		case *nerr.MultiError:
+			for i, child := range e.Errors {
+				fmt.Fprintf(b, "%s[%d]\n", indent, i)
+				formatChain(b, child, indent+"  ")
+			}
+			return
+
+		default:
+			fmt.Fprintf(b, "%s%T: %v\n", indent, err, err)
+			return
+		}
+	}
+}
+
+func formatPlace(e *nerr.Error) string {
+	frames := e.Frames()
+	if len(frames) == 0 {
+		return "?"
+	}
+
+	return fmt.Sprintf("%s (%s)", frames[0].Function, path.Base(frames[0].File))
+}
+
+func formatStableFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if !volatileFields[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, fields[k]))
+	}
+	return strings.Join(pairs, ",")
+}
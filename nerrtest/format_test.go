@@ -0,0 +1,34 @@
+package nerrtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrtest"
+)
+
+func load() error {
+	return nerr.New("repo.load", 5001, map[string]any{"id": 7}, errors.New("boom"))
+}
+
+func TestFormat(t *testing.T) {
+	nerr.Configure(nerr.WithCaptureEnvironment(true), nerr.WithCaptureTime(true), nerr.WithCaptureGoroutine(true))
+	defer nerr.Configure(nerr.WithCaptureEnvironment(false), nerr.WithCaptureTime(false), nerr.WithCaptureGoroutine(false))
+
+	err := nerr.New("batch", &nerr.MultiError{Errors: []error{load(), errors.New("skipped")}})
+
+	want := `github.com/n-r-w/nerr/nerrtest_test.TestFormat (format_test.go); op: batch
+[0]
+  github.com/n-r-w/nerr/nerrtest_test.load (format_test.go); op: repo.load; code: 5001; fields: id=7
+  *errors.errorString: boom
+[1]
+  *errors.errorString: skipped
+`
+	if got := nerrtest.Format(err); got != want {
+		t.Fatalf("Format() =\n%s\nwant\n%s", got, want)
+	}
+	if got := nerrtest.Format(nil); got != "<nil>\n" {
+		t.Fatalf("Format(nil) = %q", got)
+	}
+}
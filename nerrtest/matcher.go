@@ -0,0 +1,61 @@
+package nerrtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/n-r-w/nerr"
+)
+
+// ErrorMatcher - сопоставитель ошибок. Реализует gomock.Matcher и может быть целью errors.As:
+//
+//	mock.EXPECT().Save(gomock.Any()).Return(nerrtest.CodeMatcher(5001))
+//	require.ErrorAs(t, err, nerrtest.CodeMatcher(5001))
+type ErrorMatcher struct {
+	desc  string
+	match func(err error) bool
+}
+
+// CodeMatcher сопоставляет ошибки, в цепочке которых есть код code
+func CodeMatcher(code int) *ErrorMatcher {
+	return &ErrorMatcher{
+		desc:  fmt.Sprintf("error with code %d", code),
+		match: func(err error) bool { return nerr.IsCode(err, code) },
+	}
+}
+
+// OpMatcher сопоставляет ошибки, одна из операций которых содержит op
+func OpMatcher(op string) *ErrorMatcher {
+	return &ErrorMatcher{
+		desc: fmt.Sprintf("error with op containing %q", op),
+		match: func(err error) bool {
+			for _, o := range nerr.Ops(err) {
+				if strings.Contains(o, op) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Matches реализует gomock.Matcher
+func (m ErrorMatcher) Matches(x any) bool {
+	err, ok := x.(error)
+	return ok && m.match(err)
+}
+
+// String реализует gomock.Matcher
+func (m ErrorMatcher) String() string {
+	return m.desc
+}
+
+// Error нужен, чтобы *ErrorMatcher был допустимой целью errors.As
+func (m ErrorMatcher) Error() string {
+	return m.desc
+}
+
+// MatchError вызывается из (*nerr.Error).As
+func (m ErrorMatcher) MatchError(e *nerr.Error) bool {
+	return m.match(e)
+}
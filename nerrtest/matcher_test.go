@@ -0,0 +1,45 @@
+package nerrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/nerrtest"
+)
+
+func TestMatchers(t *testing.T) {
+	err := nerr.New("service", nerr.New("store.Save", 5001, errors.New("boom")))
+	wrapped := fmt.Errorf("handler: %w", err)
+
+	tests := []struct {
+		name    string
+		matcher *nerrtest.ErrorMatcher
+		want    bool
+		desc    string
+	}{
+		{"code", nerrtest.CodeMatcher(5001), true, "error with code 5001"},
+		{"other code", nerrtest.CodeMatcher(5002), false, "error with code 5002"},
+		{"op", nerrtest.OpMatcher("store.Save"), true, `error with op containing "store.Save"`},
+		{"other op", nerrtest.OpMatcher("cache"), false, `error with op containing "cache"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Matches(err); got != tt.want {
+				t.Fatalf("Matches() = %v, want %v", got, tt.want)
+			}
+			// errors.As находит сопоставитель через (*nerr.Error).As и за сторонней оберткой
+			if got := errors.As(wrapped, tt.matcher); got != tt.want {
+				t.Fatalf("errors.As() = %v, want %v", got, tt.want)
+			}
+			if tt.matcher.String() != tt.desc {
+				t.Fatalf("String() = %q, want %q", tt.matcher.String(), tt.desc)
+			}
+			if tt.matcher.Matches("not an error") || tt.matcher.Matches(nil) {
+				t.Fatal("non-error matched")
+			}
+		})
+	}
+}
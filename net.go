@@ -0,0 +1,37 @@
+package nerr
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+func init() {
+	// правила TLS уточняют сетевые и должны проверяться раньше них
+	MapError(IsTLSHandshakeTimeout, ErrTLSHandshakeTimeout)
+	MapError(IsCertificateHostname, ErrCertificateHostname)
+	MapError(IsCertificateInvalid, ErrCertificateInvalid)
+
+	MapError(IsNetTimeout, ErrTimeout)
+	MapError(IsNetUnavailable, ErrUnavailable)
+	MarkRetryable(func(err error) bool { return IsNetTimeout(err) || IsNetUnavailable(err) })
+}
+
+// IsNetTimeout проверяет, что в цепочке есть сетевая ошибка (net.Error) с истекшим таймаутом
+func IsNetTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsNetUnavailable проверяет, что в цепочке есть ошибка разрешения имени (*net.DNSError)
+// или соединение было отклонено, сброшено или прервано
+func IsNetUnavailable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED)
+}
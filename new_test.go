@@ -0,0 +1,90 @@
+package nerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestNewEmptyArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []any
+		wantNil bool
+	}{
+		{name: "op and nil", args: []any{"op", nil}, wantNil: false},
+		{name: "nil", args: []any{nil}, wantNil: true},
+		{name: "no args", args: nil, wantNil: true},
+		{name: "context", args: []any{context.Background()}, wantNil: true},
+		{name: "skip and nil", args: []any{nerr.Skip(1), nil}, wantNil: true},
+		{name: "zero code and nil", args: []any{0, nil}, wantNil: true},
+		{name: "empty fields and nil", args: []any{map[string]any{}, nil}, wantNil: true},
+		{name: "empty op", args: []any{""}, wantNil: true},
+		{name: "typed nil *Error", args: []any{(*nerr.Error)(nil)}, wantNil: true},
+		{name: "list of nil errors", args: []any{[]error{nil}}, wantNil: true},
+		{name: "code", args: []any{nerr.ErrTimeout}, wantNil: false},
+		{name: "fields", args: []any{map[string]any{"k": 1}}, wantNil: false},
+		{name: "cause", args: []any{errors.New("cause")}, wantNil: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := nerr.New(tt.args...)
+			if (err == nil) != tt.wantNil {
+				t.Fatalf("New(%#v) = %v, want nil: %v", tt.args, err, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestNewOpWithNilCause(t *testing.T) {
+	err := nerr.New("op", nil)
+
+	var e *nerr.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("want *nerr.Error, got %T", err)
+	}
+	if e.Op != "op" || e.Err != nil {
+		t.Fatalf("got op %q, cause %v", e.Op, e.Err)
+	}
+}
+
+func TestWrapOnceNil(t *testing.T) {
+	tests := []struct {
+		name string
+		args []any
+	}{
+		{name: "op and nil", args: []any{"op", nil}},
+		{name: "code and nil", args: []any{"op", nerr.ErrTimeout, nil}},
+		{name: "typed nil *Error", args: []any{"op", (*nerr.Error)(nil)}},
+		{name: "list of nil errors", args: []any{"op", []error{nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := nerr.WrapOnce(tt.args...); err != nil {
+				t.Fatalf("WrapOnce(%#v) = %v, want nil", tt.args, err)
+			}
+		})
+	}
+}
+
+func TestWrapOnceDefer(t *testing.T) {
+	run := func(fail bool) (err error) {
+		defer func() { err = nerr.WrapOnce("run", err) }()
+
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}
+
+	if err := run(false); err != nil {
+		t.Fatalf("successful call returned %v", err)
+	}
+	if err := run(true); err == nil || nerr.OutermostOp(err) != "run" {
+		t.Fatalf("failed call returned %v", err)
+	}
+}
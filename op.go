@@ -0,0 +1,51 @@
+package nerr
+
+import (
+	"path"
+	"strings"
+)
+
+// IsOp проверяет, что операция одного из уровней цепочки соответствует шаблону pattern в синтаксисе path.Match:
+// IsOp(err, "store.*") находит "store.get" и "store.user.save". Операции, объединенные New через запятую,
+// проверяются по отдельности. Текст сторонних ошибок не учитывается, некорректный шаблон ни с чем не совпадает
+func IsOp(err error, pattern string) bool {
+	for err != nil {
+		switch v := err.(type) {
+		case *Error:
+			if matchOp(v.Operation(), pattern) {
+				return true
+			}
+			err = v.Err
+		case *MultiError:
+			for _, e := range v.Errors {
+				if IsOp(e, pattern) {
+					return true
+				}
+			}
+			return false
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func matchOp(op, pattern string) bool {
+	if len(op) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern, op); ok {
+		return true
+	}
+	if !strings.Contains(op, ", ") {
+		return false
+	}
+
+	for _, part := range strings.Split(op, ", ") {
+		if ok, _ := path.Match(pattern, part); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package nerr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FromPanic преобразует значение, полученное recover(), в ошибку с кодом ErrPanic. Place и Stack - место паники
+// (кадры runtime пропускаются), глубина стека - Config.StackDepth или 32, если он не задан. Значение-ошибка становится
+// вложенной ошибкой, остальные значения - ее текстом. Для nil возвращается nil
+func FromPanic(v any) error {
+	if v == nil {
+		return nil
+	}
+
+	e := fromPanic(v, 1)
+	runHooks(e)
+	return e
+}
+
+// Recover перехватывает панику и записывает в *errp ошибку FromPanic. Вызывается только через defer:
+//
+//	defer nerr.Recover(&err)
+func Recover(errp *error) {
+	if v := recover(); v != nil {
+		e := fromPanic(v, 1)
+		runHooks(e)
+		*errp = e
+	}
+}
+
+// fromPanic создает ошибку паники. skip - как у callersDepth
+func fromPanic(v any, skip int) *Error {
+	cause, ok := v.(error)
+	if !ok {
+		cause = &panicValue{v: v}
+	}
+
+	e := newError(noTrace, []any{ErrPanic, cause})
+
+	depth := cfg().StackDepth
+	if depth <= 0 {
+		depth = defaultWithStackDepth
+	}
+	// запас на кадры функции, вызвавшей recover, и runtime
+	stack := panicFrames(callersDepth(skip+1, depth+8))
+	if len(stack) > depth {
+		stack = stack[:depth]
+	}
+	if len(stack) > 0 {
+		e.Place = stack[0].String()
+		e.Stack = stack
+	}
+
+	if cfg().CaptureGoroutine {
+		e.Goroutine = goroutineID()
+	}
+
+	return e
+}
+
+// panicFrames отбрасывает кадры до паники: функцию, вызвавшую recover, и кадры runtime (runtime.gopanic и т.п.).
+// Если кадров runtime нет (вызов не во время паники), стек возвращается без изменений
+func panicFrames(frames []Frame) []Frame {
+	i := 0
+	for i < len(frames) && !strings.HasPrefix(frames[i].Function, "runtime.") {
+		i++
+	}
+	if i == len(frames) {
+		return frames
+	}
+
+	for i < len(frames) && strings.HasPrefix(frames[i].Function, "runtime.") {
+		i++
+	}
+	return frames[i:]
+}
+
+// panicValue - значение паники, не являющееся ошибкой
+type panicValue struct {
+	v any
+}
+
+func (p *panicValue) Error() string {
+	return fmt.Sprintf("panic: %v", p.v)
+}
+
+// PanicValue возвращает исходное значение паники из ошибки, созданной FromPanic или Recover, в том числе обернутой
+func PanicValue(err error) (any, bool) {
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			return nil, false
+		}
+
+		if e.Code == ErrPanic && e.Err != nil {
+			if p, ok := e.Err.(*panicValue); ok {
+				return p.v, true
+			}
+			return e.Err, true
+		}
+		err = e.Err
+	}
+	return nil, false
+}
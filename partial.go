@@ -0,0 +1,126 @@
+package nerr
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// FieldItemIndex - индекс элемента пакета, обработка которого завершилась ошибкой (см. PartialResult)
+const FieldItemIndex = "item.index"
+
+// PartialResult - результат пакетной операции, часть элементов которой может завершиться ошибкой: успешно
+// обработанные элементы и ошибки остальных с индексом элемента в поле FieldItemIndex.
+// Безопасен для конкурентного использования
+type PartialResult[T any] struct {
+	mu    sync.Mutex
+	items []T
+	errs  []error
+}
+
+// Add добавляет успешно обработанный элемент
+func (r *PartialResult[T]) Add(item T) {
+	r.mu.Lock()
+	r.items = append(r.items, item)
+	r.mu.Unlock()
+}
+
+// Fail добавляет ошибку обработки элемента с индексом index. nil игнорируется.
+// Для *Error сохраняется копия, сторонняя ошибка оборачивается
+func (r *PartialResult[T]) Fail(index int, err error) {
+	if err == nil {
+		return
+	}
+
+	e := cloneOrWrap(err)
+	setField(e, FieldItemIndex, index)
+
+	r.mu.Lock()
+	r.errs = append(r.errs, e)
+	r.mu.Unlock()
+}
+
+// Items возвращает успешно обработанные элементы в порядке добавления
+func (r *PartialResult[T]) Items() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]T, len(r.items))
+	copy(res, r.items)
+	return res
+}
+
+// Failed возвращает количество элементов, обработка которых завершилась ошибкой
+func (r *PartialResult[T]) Failed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.errs)
+}
+
+// Err возвращает nil, если ошибок не было, иначе *MultiError с ошибками элементов, упорядоченными по индексу.
+// NewAPIEnvelope выводит их в details с полем "items[N]"
+func (r *PartialResult[T]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(r.errs))
+	copy(errs, r.errs)
+	sort.SliceStable(errs, func(i, j int) bool { return itemIndex(errs[i]) < itemIndex(errs[j]) })
+	return &MultiError{Errors: errs}
+}
+
+// ItemIndex возвращает индекс элемента пакета из поля FieldItemIndex
+func ItemIndex(err error) (int, bool) {
+	v, ok := Field(err, FieldItemIndex)
+	if !ok {
+		return 0, false
+	}
+
+	switch i := v.(type) {
+	case int:
+		return i, true
+	case float64:
+		// после разбора Envelope
+		return int(i), true
+	}
+	return 0, false
+}
+
+func itemIndex(err error) int {
+	i, _ := ItemIndex(err)
+	return i
+}
+
+// itemDetails возвращает уточнения для ошибок элементов пакета (см. PartialResult): ошибки проверки полей элемента
+// с полем "items[N].field" или одно уточнение "items[N]" с сообщением для клиента
+func itemDetails(err error) []APIDetail {
+	var m *MultiError
+	if !errors.As(err, &m) {
+		return nil
+	}
+
+	var res []APIDetail
+	for _, item := range m.Errors {
+		index, ok := ItemIndex(item)
+		if !ok {
+			continue
+		}
+
+		prefix := "items[" + strconv.Itoa(index) + "]"
+		if list := validationList(item); len(list) > 0 {
+			for _, v := range list {
+				res = append(res, APIDetail{Field: prefix + "." + v.Field, Rule: v.Rule, Message: v.Message})
+			}
+			continue
+		}
+
+		res = append(res, APIDetail{Field: prefix, Rule: CodeName(OutermostCode(item)), Message: PublicMessage(item)})
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package pgerr
+
+import (
+	"github.com/jackc/pgconn"
+	"github.com/n-r-w/nerr"
+)
+
+// Поля ошибок пакетных операций
+const (
+	// FieldBatchIndex - индекс запроса пакета, завершившегося ошибкой (с нуля)
+	FieldBatchIndex = "pg.batch_index"
+	// FieldCopyRows - количество строк, скопированных CopyFrom до ошибки
+	FieldCopyRows = "pg.copy_rows"
+	// FieldSQLState - код SQLSTATE ошибки PostgreSQL
+	FieldSQLState = "pg.sqlstate"
+)
+
+// BatchResults - результаты пакета запросов, например pgx.BatchResults
+type BatchResults interface {
+	Exec() (pgconn.CommandTag, error)
+	Close() error
+}
+
+// ExecBatch читает результаты n запросов пакета и закрывает его. Первая ошибка возвращается
+// с индексом запроса (см. BatchError), ошибка закрытия - если запросы выполнены успешно
+func ExecBatch(br BatchResults, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := br.Exec(); err != nil {
+			_ = br.Close()
+			return batchError(err, i)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return nerr.New(nerr.Skip(1), "pg.batch", err)
+	}
+	return nil
+}
+
+// BatchError оборачивает ошибку запроса пакета с индексом index, записывая индекс и SQLSTATE в поля
+func BatchError(err error, index int) error {
+	return batchError(err, index)
+}
+
+// CopyFromError оборачивает ошибку CopyFrom, записывая количество скопированных строк и SQLSTATE в поля
+func CopyFromError(err error, copied int64) error {
+	if err == nil {
+		return nil
+	}
+
+	return nerr.New(nerr.Skip(1), "pg.copy_from", withSQLState(map[string]any{FieldCopyRows: copied}, err), err)
+}
+
+func batchError(err error, index int) error {
+	if err == nil {
+		return nil
+	}
+
+	// уровень 3 - функция, вызвавшая ExecBatch или BatchError
+	return nerr.New(nerr.Skip(2), "pg.batch", withSQLState(map[string]any{FieldBatchIndex: index}, err), err)
+}
+
+// withSQLState добавляет к полям fields код SQLSTATE ошибки err
+func withSQLState(fields map[string]any, err error) map[string]any {
+	if code := SqlCode(err); len(code) > 0 {
+		fields[FieldSQLState] = code
+	}
+	return fields
+}
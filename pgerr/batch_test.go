@@ -0,0 +1,102 @@
+package pgerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+	"github.com/n-r-w/nerr"
+	"github.com/n-r-w/nerr/pgerr"
+)
+
+// batchResults возвращает ошибку err для запроса с индексом failAt
+type batchResults struct {
+	failAt int
+	err    error
+	calls  int
+	closed bool
+}
+
+func (b *batchResults) Exec() (pgconn.CommandTag, error) {
+	defer func() { b.calls++ }()
+	if b.calls == b.failAt {
+		return nil, b.err
+	}
+	return nil, nil
+}
+
+func (b *batchResults) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestBatchFieldsBeforeHooks(t *testing.T) {
+	unique := &pq.Error{Code: "23505"}
+
+	tests := []struct {
+		name   string
+		make   func() error
+		key    string
+		want   any
+		masked bool
+	}{
+		{"ExecBatch", func() error { return pgerr.ExecBatch(&batchResults{failAt: 2, err: unique}, 3) }, pgerr.FieldBatchIndex, 2, false},
+		{"BatchError", func() error { return pgerr.BatchError(unique, 4) }, pgerr.FieldBatchIndex, 4, false},
+		{"CopyFromError", func() error { return pgerr.CopyFromError(unique, 10) }, pgerr.FieldCopyRows, int64(10), false},
+		{"CopyFromError redacted", func() error { return pgerr.CopyFromError(unique, 10) }, pgerr.FieldCopyRows, "***", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen map[string]any
+			opts := []nerr.Option{nerr.WithHook(func(e *nerr.Error) {
+				seen = make(map[string]any, len(e.Fields))
+				for k, v := range e.Fields {
+					seen[k] = v
+				}
+			})}
+			if tt.masked {
+				opts = append(opts, nerr.WithRedact(func(key string, value any) any {
+					if key == pgerr.FieldCopyRows {
+						return "***"
+					}
+					return value
+				}))
+			}
+			nerr.Configure(opts...)
+			defer nerr.Configure(nerr.WithoutHooks(), nerr.WithRedact(nil))
+
+			err := tt.make()
+
+			if got, _ := nerr.Field(err, tt.key); got != tt.want {
+				t.Fatalf("%s = %#v, want %#v", tt.key, got, tt.want)
+			}
+			if seen[tt.key] != tt.want {
+				t.Fatalf("hook saw %s = %#v, want %#v", tt.key, seen[tt.key], tt.want)
+			}
+			if seen[pgerr.FieldSQLState] != "23505" {
+				t.Fatalf("hook saw %s = %#v", pgerr.FieldSQLState, seen[pgerr.FieldSQLState])
+			}
+			if !errors.Is(err, unique) {
+				t.Fatal("cause lost")
+			}
+		})
+	}
+}
+
+func TestBatchNil(t *testing.T) {
+	br := &batchResults{failAt: -1}
+	if err := pgerr.ExecBatch(br, 3); err != nil {
+		t.Fatalf("ExecBatch() = %v", err)
+	}
+	if !br.closed {
+		t.Fatal("batch not closed")
+	}
+	if err := pgerr.CopyFromError(nil, 1); err != nil {
+		t.Fatalf("CopyFromError(nil) = %v", err)
+	}
+	if err := pgerr.BatchError(nil, 1); err != nil {
+		t.Fatalf("BatchError(nil) = %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/n-r-w/nerr/pgerr
+
+go 1.19
+
+require (
+	github.com/jackc/pgconn v1.12.1
+	github.com/lib/pq v1.10.6
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+)
+
+require (
+	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
+	github.com/jackc/pgio v1.0.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgproto3/v2 v2.3.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20200714003250-2b9c44734f2b // indirect
+	github.com/n-r-w/eno v1.0.1 // indirect
+	golang.org/x/crypto v0.0.0-20210711020723-a769d52b0f97 // indirect
+	golang.org/x/text v0.3.7 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
@@ -0,0 +1,79 @@
+// Package pgerr - сведения из ошибок драйверов PostgreSQL github.com/lib/pq и github.com/jackc/pgconn.
+// Коды SQLSTATE и предикаты на их основе доступны в пакете nerr без зависимости от драйверов
+package pgerr
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/lib/pq"
+	"github.com/n-r-w/nerr"
+)
+
+// Details - сведения об ошибке сервера PostgreSQL
+type Details struct {
+	Severity   string
+	Code       string
+	Message    string
+	Detail     string
+	Hint       string
+	Schema     string
+	Table      string
+	Column     string
+	DataType   string
+	Constraint string
+	Where      string
+	Routine    string
+}
+
+// ErrorDetails возвращает сведения об ошибке PostgreSQL из любого места цепочки
+func ErrorDetails(err error) (Details, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return Details{
+			Severity:   pqErr.Severity,
+			Code:       string(pqErr.Code),
+			Message:    pqErr.Message,
+			Detail:     pqErr.Detail,
+			Hint:       pqErr.Hint,
+			Schema:     pqErr.Schema,
+			Table:      pqErr.Table,
+			Column:     pqErr.Column,
+			DataType:   pqErr.DataTypeName,
+			Constraint: pqErr.Constraint,
+			Where:      pqErr.Where,
+			Routine:    pqErr.Routine,
+		}, true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return Details{
+			Severity:   pgErr.Severity,
+			Code:       pgErr.Code,
+			Message:    pgErr.Message,
+			Detail:     pgErr.Detail,
+			Hint:       pgErr.Hint,
+			Schema:     pgErr.SchemaName,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			DataType:   pgErr.DataTypeName,
+			Constraint: pgErr.ConstraintName,
+			Where:      pgErr.Where,
+			Routine:    pgErr.Routine,
+		}, true
+	}
+
+	return Details{}, false
+}
+
+// PgDetails возвращает имена ограничения, таблицы и столбца, а также детали ошибки PostgreSQL из любого места цепочки
+func PgDetails(err error) (constraint, table, column, detail string, ok bool) {
+	d, ok := ErrorDetails(err)
+	return d.Constraint, d.Table, d.Column, d.Detail, ok
+}
+
+// SqlCode возвращает SQLSTATE ошибки PostgreSQL из любого места цепочки
+func SqlCode(err error) string {
+	return nerr.SqlCode(err)
+}
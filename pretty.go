@@ -0,0 +1,126 @@
+package nerr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pretty возвращает многострочное представление цепочки в виде дерева с отступами: для каждого уровня
+// операция и код, место возникновения, стек (без кадров, общих со стеком вложенной ошибки) и вложенные поля,
+// в конце - ссылка на документацию (DocURL). Предназначено для CLI и локальной разработки
+func Pretty(err error) string {
+	if err == nil {
+		return "<nil>\n"
+	}
+
+	var b strings.Builder
+	writePretty(&b, err, "", palette{})
+	writeDocURL(&b, err, palette{})
+	return b.String()
+}
+
+func writeDocURL(b *strings.Builder, err error, p palette) {
+	if url := DocURL(err); len(url) > 0 {
+		b.WriteString("docs: " + p.place + url + p.reset + "\n")
+	}
+}
+
+func writePretty(b *strings.Builder, err error, indent string, p palette) {
+	limit := cfg().MaxChainDepth
+	// горутина, в которой обернут предыдущий уровень, если он создан в другой горутине
+	var wrappedIn uint64
+	// предыдущий уровень, для отделения уровней из других компонентов
+	var prev *Error
+	for depth := 0; err != nil; depth++ {
+		switch v := err.(type) {
+		case *Error:
+			if limit > 0 && depth == limit {
+				b.WriteString(indent + moreLevels(v) + "\n")
+				return
+			}
+
+			if mark, ok := remoteMark(v); ok && (prev == nil || !sameOrigin(prev, v)) {
+				b.WriteString(indent + "--- " + mark + " ---\n")
+			}
+			prev = v
+
+			b.WriteString(indent)
+			b.WriteString(prettyHeader(v, p))
+			b.WriteByte('\n')
+
+			if len(v.Place) > 0 {
+				b.WriteString(indent + "  at " + p.place + v.Place + p.reset + "\n")
+			}
+
+			// стек, полностью совпадающий со стеком вложенной ошибки, не выводится
+			if own, shared := ownFrames(v); len(v.Stack) > 1 && len(own) > 0 {
+				switch {
+				case crossesGoroutine(v):
+					fmt.Fprintf(b, "%s  stack (wrapped in goroutine %d):\n", indent, v.Goroutine)
+				case wrappedIn != 0:
+					fmt.Fprintf(b, "%s  stack (created in goroutine %d):\n", indent, v.Goroutine)
+				default:
+					b.WriteString(indent + "  stack:\n")
+				}
+				for _, f := range own {
+					b.WriteString(indent + "    " + p.place + f.String() + p.reset + "\n")
+				}
+				if shared > 0 {
+					fmt.Fprintf(b, "%s    … %d frames shared with cause\n", indent, shared)
+				}
+			}
+
+			if len(v.Fields) > 0 {
+				b.WriteString(indent + "  fields:\n")
+				keys := make([]string, 0, len(v.Fields))
+				for k := range v.Fields {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					fmt.Fprintf(b, "%s    %s: %s\n", indent, SanitizeString(k), SanitizeString(fmt.Sprint(v.Fields[k])))
+				}
+			}
+
+			wrappedIn = 0
+			if crossesGoroutine(v) {
+				wrappedIn = v.Goroutine
+			}
+
+			err = v.Err
+			indent += "  "
+
+		case *MultiError:
+			for i, child := range v.Errors {
+				fmt.Fprintf(b, "%s[%d]\n", indent, i)
+				writePretty(b, child, indent+"  ", p)
+			}
+			if v.Dropped > 0 {
+				b.WriteString(indent + droppedMark(v.Dropped) + "\n")
+			}
+			if len(v.Groups) > 0 {
+				b.WriteString(indent + groupsMark(v.Groups) + "\n")
+			}
+			return
+
+		default:
+			b.WriteString(indent + "cause: " + p.cause + messageText(err.Error()) + p.reset + "\n")
+			return
+		}
+	}
+}
+
+func prettyHeader(e *Error, p palette) string {
+	var parts []string
+	if op := e.Operation(); len(op) > 0 {
+		parts = append(parts, "op: "+p.op+SanitizeString(op)+p.reset)
+	}
+	if e.Code != 0 {
+		parts = append(parts, fmt.Sprintf("code: %s%d%s", p.code, e.Code, p.reset))
+	}
+	if len(parts) == 0 {
+		return "error"
+	}
+	return strings.Join(parts, ", ")
+}
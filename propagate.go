@@ -0,0 +1,47 @@
+package nerr
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Ключи сводки об ошибке, передаваемой между сервисами в заголовках HTTP или трейлерах gRPC
+const (
+	HeaderCode = "X-Error-Code"
+	HeaderOp   = "X-Error-Op"
+)
+
+// EncodeSummary передает в set краткую сводку об ошибке: код (OutermostCode) и внешнюю операцию цепочки.
+// Операция кодируется, чтобы быть допустимым значением заголовка. Пустые значения не передаются
+func EncodeSummary(err error, set func(key, value string)) {
+	if err == nil {
+		return
+	}
+
+	if code := OutermostCode(err); code != 0 {
+		set(HeaderCode, strconv.Itoa(code))
+	}
+
+	for _, op := range Ops(err) {
+		if len(op) > 0 {
+			set(HeaderOp, url.QueryEscape(op))
+			break
+		}
+	}
+}
+
+// DecodeSummary восстанавливает ошибку по сводке EncodeSummary, получая значения с помощью get.
+// Если сводки нет, возвращается nil
+func DecodeSummary(get func(key string) string) *Error {
+	code, _ := strconv.Atoi(get(HeaderCode))
+
+	op, err := url.QueryUnescape(get(HeaderOp))
+	if err != nil {
+		op = ""
+	}
+
+	if code == 0 && len(op) == 0 {
+		return nil
+	}
+	return &Error{Op: op, Code: code}
+}
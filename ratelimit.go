@@ -0,0 +1,124 @@
+package nerr
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimitedReporter передает в Next не более Limit ошибок с одинаковым ключом за Interval.
+// Остальные ошибки отбрасываются и учитываются в счетчиках
+type RateLimitedReporter struct {
+	Next Reporter
+	// Limit - количество ошибок с одним ключом за Interval. Limit <= 0 снимает ограничение
+	Limit    int
+	Interval time.Duration
+
+	// Key определяет ключ ограничения. По умолчанию Fingerprint
+	Key func(e *Error) string
+	// SampleEvery, если больше нуля, пропускает каждую SampleEvery-ю ошибку сверх лимита
+	SampleEvery int
+
+	mu         sync.Mutex
+	windows    map[string]*rateWindow
+	lastSweep  time.Time
+	suppressed uint64
+}
+
+type rateWindow struct {
+	start      time.Time
+	count      int
+	suppressed uint64
+}
+
+// NewRateLimitedReporter создает ограничитель с ключом по Fingerprint. limit <= 0 снимает ограничение
+func NewRateLimitedReporter(next Reporter, limit int, interval time.Duration) *RateLimitedReporter {
+	return &RateLimitedReporter{
+		Next:     next,
+		Limit:    limit,
+		Interval: interval,
+	}
+}
+
+func (r *RateLimitedReporter) Report(ctx context.Context, e *Error) {
+	if r.allow(e) {
+		r.Next.Report(ctx, e)
+	}
+}
+
+// Suppressed возвращает общее количество отброшенных ошибок
+func (r *RateLimitedReporter) Suppressed() uint64 {
+	return atomic.LoadUint64(&r.suppressed)
+}
+
+// SuppressedByKey возвращает количество ошибок, отброшенных в текущем интервале, по ключам
+func (r *RateLimitedReporter) SuppressedByKey() map[string]uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make(map[string]uint64, len(r.windows))
+	for k, w := range r.windows {
+		if w.suppressed > 0 {
+			res[k] = w.suppressed
+		}
+	}
+	return res
+}
+
+func (r *RateLimitedReporter) allow(e *Error) bool {
+	if r.Limit <= 0 {
+		return true
+	}
+
+	key := r.key(e)
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.windows == nil {
+		r.windows = make(map[string]*rateWindow)
+	}
+	r.sweep(now)
+
+	w, ok := r.windows[key]
+	if !ok || now.Sub(w.start) >= r.Interval {
+		w = &rateWindow{start: now}
+		r.windows[key] = w
+	}
+
+	w.count++
+	if w.count <= r.Limit {
+		return true
+	}
+
+	if r.SampleEvery > 0 && (w.count-r.Limit)%r.SampleEvery == 0 {
+		return true
+	}
+
+	w.suppressed++
+	atomic.AddUint64(&r.suppressed, 1)
+	return false
+}
+
+func (r *RateLimitedReporter) key(e *Error) string {
+	if r.Key != nil {
+		return r.Key(e)
+	}
+	return Fingerprint(e)
+}
+
+// sweep удаляет истекшие интервалы, чтобы карта не росла бесконечно
+func (r *RateLimitedReporter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.Interval {
+		return
+	}
+
+	for k, w := range r.windows {
+		if now.Sub(w.start) >= r.Interval {
+			delete(r.windows, k)
+		}
+	}
+	r.lastSweep = now
+}
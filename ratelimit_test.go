@@ -0,0 +1,53 @@
+package nerr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/n-r-w/nerr"
+)
+
+type countingReporter struct {
+	n int
+}
+
+func (r *countingReporter) Report(context.Context, *nerr.Error) {
+	r.n++
+}
+
+func TestRateLimitedReporter(t *testing.T) {
+	tests := []struct {
+		name           string
+		limit          int
+		sampleEvery    int
+		wantReported   int
+		wantSuppressed uint64
+	}{
+		{"zero limit", 0, 0, 10, 0},
+		{"negative limit", -1, 0, 10, 0},
+		{"limit", 3, 0, 3, 7},
+		{"limit with sampling", 3, 2, 6, 4},
+	}
+
+	e := nerr.New("op", nerr.ErrUnavailable).(*nerr.Error)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &countingReporter{}
+			r := nerr.NewRateLimitedReporter(next, tt.limit, time.Hour)
+			r.SampleEvery = tt.sampleEvery
+
+			for i := 0; i < 10; i++ {
+				r.Report(context.Background(), e)
+			}
+
+			if next.n != tt.wantReported {
+				t.Fatalf("reported %d, want %d", next.n, tt.wantReported)
+			}
+			if got := r.Suppressed(); got != tt.wantSuppressed {
+				t.Fatalf("suppressed %d, want %d", got, tt.wantSuppressed)
+			}
+		})
+	}
+}
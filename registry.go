@@ -0,0 +1,42 @@
+package nerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// registry - редко изменяемый список, чтение которого не требует блокировок
+type registry[T any] struct {
+	mu    sync.Mutex
+	count int32
+	list  atomic.Value // []T
+}
+
+func (r *registry[T]) add(v T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.items()
+	list := make([]T, len(old), len(old)+1)
+	copy(list, old)
+	r.list.Store(append(list, v))
+
+	atomic.AddInt32(&r.count, 1)
+}
+
+func (r *registry[T]) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	atomic.StoreInt32(&r.count, 0)
+	r.list.Store([]T{})
+}
+
+func (r *registry[T]) empty() bool {
+	return atomic.LoadInt32(&r.count) == 0
+}
+
+func (r *registry[T]) items() []T {
+	list, _ := r.list.Load().([]T)
+	return list
+}
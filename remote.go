@@ -0,0 +1,79 @@
+package nerr
+
+// remoteOrigin - отметка уровня, восстановленного из представления другого компонента
+type remoteOrigin struct {
+	service string
+}
+
+// WithService задает Config.Service
+func WithService(name string) Option {
+	return func(c *Config) {
+		c.Service = name
+	}
+}
+
+// WithRemote возвращает копию уровня err, отмеченную как полученную из сервиса service (пустое имя - сервис
+// неизвестен). Используется при восстановлении ошибок из сериализованного представления, например в nerrpb.
+// Сторонняя ошибка оборачивается
+func WithRemote(err error, service string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+	e.values = append(e.values, remoteOrigin{service: service})
+	return e
+}
+
+// Remote сообщает, восстановлен ли уровень из представления другого компонента (ErrorFromEnvelope, nerrpb.FromProto),
+// и возвращает имя сервиса, в котором он создан. Кадры Place и Stack такого уровня относятся к другому процессу
+func (e *Error) Remote() (service string, ok bool) {
+	for i := len(e.values) - 1; i >= 0; i-- {
+		if r, ok := e.values[i].(remoteOrigin); ok {
+			return r.service, true
+		}
+	}
+	return "", false
+}
+
+// IsRemote сообщает, содержит ли цепочка уровни, восстановленные из представления другого компонента
+func IsRemote(err error) bool {
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			return false
+		}
+		if _, ok := e.Remote(); ok {
+			return true
+		}
+		err = e.Err
+	}
+	return false
+}
+
+// sameOrigin сообщает, созданы ли уровни в одном компоненте: кадры уровней из разных компонентов не сравниваются
+func sameOrigin(a, b *Error) bool {
+	sa, ra := a.Remote()
+	sb, rb := b.Remote()
+	return ra == rb && sa == sb
+}
+
+// Service возвращает сервис, в котором создан уровень: для восстановленных уровней - исходный, иначе Config.Service
+func (e *Error) Service() string {
+	if service, ok := e.Remote(); ok {
+		return service
+	}
+	return cfg().Service
+}
+
+// remoteMark возвращает отметку восстановленного уровня для текстового вывода: "remote" или "remote service"
+func remoteMark(e *Error) (string, bool) {
+	service, ok := e.Remote()
+	if !ok {
+		return "", false
+	}
+	if len(service) == 0 {
+		return "remote", true
+	}
+	return "remote " + SanitizeString(service), true
+}
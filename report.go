@@ -0,0 +1,76 @@
+package nerr
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Reporter - получатель ошибок для внешних систем (Sentry, Bugsnag, журналы и т.п.)
+type Reporter interface {
+	Report(ctx context.Context, e *Error)
+}
+
+// ReporterFunc позволяет использовать функцию как Reporter
+type ReporterFunc func(ctx context.Context, e *Error)
+
+func (f ReporterFunc) Report(ctx context.Context, e *Error) {
+	f(ctx, e)
+}
+
+var reporters registry[Reporter]
+
+// AddReporter регистрирует получателя для Report
+func AddReporter(r Reporter) {
+	reporters.add(r)
+}
+
+// ResetReporters удаляет всех зарегистрированных получателей
+func ResetReporters() {
+	reporters.reset()
+}
+
+// Report передает ошибку всем зарегистрированным получателям. Сторонние ошибки предварительно оборачиваются в *Error
+func Report(ctx context.Context, err error) {
+	if err == nil || reporters.empty() {
+		return
+	}
+
+	e, ok := err.(*Error)
+	if !ok {
+		if e = newError(2, []any{err}); e == nil {
+			return
+		}
+	}
+
+	for _, r := range reporters.items() {
+		r.Report(ctx, e)
+	}
+}
+
+// NopReporter ничего не делает
+var NopReporter Reporter = ReporterFunc(func(context.Context, *Error) {})
+
+// LogReporter пишет ошибку вместе с трассой в Logger (log.Default(), если не задан)
+type LogReporter struct {
+	Logger *log.Logger
+}
+
+func (r LogReporter) Report(_ context.Context, e *Error) {
+	logger := r.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	logger.Printf("%v\n\t%s", e, strings.Join(e.Trace(), "\n\t"))
+}
+
+// ChanReporter отправляет ошибки в канал без блокировки: если канал заполнен, ошибка отбрасывается
+type ChanReporter chan *Error
+
+func (r ChanReporter) Report(_ context.Context, e *Error) {
+	select {
+	case r <- e:
+	default:
+	}
+}
@@ -0,0 +1,92 @@
+package nerr
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// FieldAttempts - поле итоговой ошибки Retry с количеством сделанных попыток
+const FieldAttempts = "attempts"
+
+// RetryPolicy - параметры повторов для Retry
+type RetryPolicy struct {
+	// MaxAttempts - общее количество попыток, включая первую. По умолчанию 3
+	MaxAttempts int
+	// InitialDelay - пауза перед второй попыткой. По умолчанию 100 мс
+	InitialDelay time.Duration
+	// MaxDelay - верхняя граница паузы. 0 - без ограничения
+	MaxDelay time.Duration
+	// Multiplier - множитель паузы после каждой попытки. По умолчанию 2
+	Multiplier float64
+	// Jitter включает случайное уменьшение паузы (до половины), чтобы клиенты не повторяли запросы синхронно
+	Jitter bool
+	// Retryable определяет, нужно ли повторять операцию. По умолчанию IsRetryable
+	Retryable func(err error) bool
+}
+
+// Retry выполняет fn, пока она возвращает ошибку, признанную временной, но не более policy.MaxAttempts раз.
+// Пауза не меньше RetryAfter ошибки, если он задан. В итоговую ошибку записывается поле FieldAttempts
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	policy = policy.withDefaults()
+
+	delay := policy.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+			return retryError(err, attempt)
+		}
+
+		wait := policy.delay(delay)
+		if after, ok := RetryAfter(err); ok && after > wait {
+			wait = after
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retryError(err, attempt)
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * policy.Multiplier)
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 100 * time.Millisecond
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+	return p
+}
+
+func (p RetryPolicy) delay(d time.Duration) time.Duration {
+	if p.Jitter && d > 1 {
+		d -= time.Duration(rand.Int63n(int64(d / 2)))
+	}
+	return d
+}
+
+func retryError(err error, attempts int) error {
+	e := newError(3, []any{err})
+	setField(e, FieldAttempts, attempts)
+	runHooks(e)
+	return e
+}
@@ -0,0 +1,78 @@
+package nerr
+
+import "time"
+
+// FieldRetryAfter - рекомендуемая пауза перед повтором операции (time.Duration, см. WithRetryAfter)
+const FieldRetryAfter = "retry_after"
+
+var retryRules registry[func(err error) bool]
+
+func init() {
+	MarkRetryable(func(err error) bool { return IsCode(err, ErrUnavailable) || IsCode(err, ErrTimeout) })
+	MarkRetryable(IsRetryableSQL)
+	MarkRetryable(func(err error) bool {
+		_, ok := RetryAfter(err)
+		return ok
+	})
+}
+
+// MarkRetryable регистрирует правило, по которому ошибка считается временной. Вызывать при инициализации
+func MarkRetryable(match func(err error) bool) {
+	retryRules.add(match)
+}
+
+// ResetRetryable удаляет все правила MarkRetryable, включая встроенные
+func ResetRetryable() {
+	retryRules.reset()
+}
+
+// IsRetryable проверяет, что операцию, вернувшую ошибку, имеет смысл повторить
+func IsRetryable(err error) bool {
+	if err == nil || retryRules.empty() {
+		return false
+	}
+
+	for _, match := range retryRules.items() {
+		if match(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRetryAfter задает паузу перед повтором операции в поле FieldRetryAfter. Такая ошибка считается временной
+// (IsRetryable), Retry ждет не меньше d, httperr выводит паузу в заголовке Retry-After, grpcerr - в RetryInfo.
+// Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+
+	setField(e, FieldRetryAfter, d)
+	return e
+}
+
+// RetryAfter возвращает положительную паузу перед повтором из поля FieldRetryAfter. Кроме time.Duration
+// поддерживаются значения после разбора Envelope: число наносекунд и строка в формате time.ParseDuration
+func RetryAfter(err error) (time.Duration, bool) {
+	v, ok := Field(err, FieldRetryAfter)
+	if !ok {
+		return 0, false
+	}
+
+	var d time.Duration
+	switch val := v.(type) {
+	case time.Duration:
+		d = val
+	case int64:
+		d = time.Duration(val)
+	case float64:
+		d = time.Duration(val)
+	case string:
+		d, _ = time.ParseDuration(val)
+	}
+
+	return d, d > 0
+}
@@ -0,0 +1,61 @@
+package nerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// StackSampler решает, сохранять ли полный стек (Config.StackDepth) для ошибки, созданной в месте place.
+// Без StackSampler стек сохраняется для каждой ошибки
+type StackSampler interface {
+	SampleStack(place Frame) bool
+}
+
+// StackSamplerFunc - функция, реализующая StackSampler
+type StackSamplerFunc func(place Frame) bool
+
+func (f StackSamplerFunc) SampleStack(place Frame) bool {
+	return f(place)
+}
+
+// EveryNth сохраняет полный стек для первой и затем каждой n-й ошибки из одного места возникновения
+type EveryNth struct {
+	n      uint64
+	counts sync.Map // Frame -> *uint64
+}
+
+// NewEveryNth создает StackSampler, сохраняющий стек для 1 из n ошибок из одного места
+func NewEveryNth(n int) *EveryNth {
+	if n < 1 {
+		n = 1
+	}
+	return &EveryNth{n: uint64(n)}
+}
+
+func (s *EveryNth) SampleStack(place Frame) bool {
+	v, ok := s.counts.Load(place)
+	if !ok {
+		v, _ = s.counts.LoadOrStore(place, new(uint64))
+	}
+
+	return (atomic.AddUint64(v.(*uint64), 1)-1)%s.n == 0
+}
+
+// captureStack возвращает стек, начиная с уровня skip относительно вызвавшей функции, и признак того,
+// что он полный и должен быть сохранен в Error.Stack. Если StackSampler отказал, определяется только место возникновения
+func captureStack(c *Config, skip int) ([]Frame, bool) {
+	if c.StackDepth <= 0 {
+		return callersDepth(skip+1, 1), false
+	}
+
+	if c.StackSampler == nil {
+		return callersDepth(skip+1, c.StackDepth), true
+	}
+
+	place := callersDepth(skip+1, 1)
+	if len(place) == 0 || !c.StackSampler.SampleStack(place[0]) {
+		return place, false
+	}
+
+	return callersDepth(skip+1, c.StackDepth), true
+}
@@ -0,0 +1,79 @@
+package nerr
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sanitize - очистка при выводе строк, которые могут содержать данные запроса: операций, текстов сторонних ошибок и
+// значений полей. Защищает журналы от подделки записей и нечитаемых символов. Применяется к Error(), Trace, Pretty,
+// Logfmt и Compact, но не к JSON и Envelope
+type Sanitize struct {
+	// Enabled включает очистку: управляющие и невидимые символы форматирования удаляются, переводы строк и табуляция
+	// заменяются пробелом, некорректные последовательности UTF-8 - символом U+FFFD
+	Enabled bool
+	// EscapeNewlines - выводить переводы строк как \n и \r вместо пробела
+	EscapeNewlines bool
+	// MaxRunes - максимальная длина строки в символах. Более длинные строки обрезаются с отметкой "…". 0 - без ограничения
+	MaxRunes int
+}
+
+// SanitizeString очищает строку по правилам Config.Sanitize. Используется адаптерами, выводящими строки ошибок
+// в текстовом виде
+func SanitizeString(s string) string {
+	return sanitize(cfg().Sanitize, s)
+}
+
+func sanitize(opts Sanitize, s string) string {
+	if !opts.Enabled || sanitized(opts, s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	n := 0
+	for i, r := range s {
+		if opts.MaxRunes > 0 && n == opts.MaxRunes {
+			if i < len(s) {
+				b.WriteString("…")
+			}
+			break
+		}
+
+		switch {
+		case r == '\n' || r == '\r':
+			if opts.EscapeNewlines {
+				if r == '\n' {
+					b.WriteString(`\n`)
+				} else {
+					b.WriteString(`\r`)
+				}
+			} else {
+				b.WriteByte(' ')
+			}
+		case r == '\t':
+			b.WriteByte(' ')
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		n++
+	}
+
+	return b.String()
+}
+
+// sanitized проверяет, что строка не требует очистки
+func sanitized(opts Sanitize, s string) bool {
+	n := 0
+	for _, r := range s {
+		if r == utf8.RuneError || r == '\t' || unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return false
+		}
+		n++
+	}
+	return opts.MaxRunes <= 0 || n <= opts.MaxRunes
+}
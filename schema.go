@@ -0,0 +1,15 @@
+package nerr
+
+import (
+	_ "embed"
+)
+
+//go:embed envelope.schema.json
+var envelopeSchema []byte
+
+// EnvelopeSchema возвращает JSON Schema представления ошибки, которое формирует MarshalJSON и разбирает ParseEnvelope
+func EnvelopeSchema() []byte {
+	res := make([]byte, len(envelopeSchema))
+	copy(res, envelopeSchema)
+	return res
+}
@@ -0,0 +1,173 @@
+package nerr
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorView - неизменяемый снимок цепочки ошибок (см. Snapshot). Не ссылается на исходную ошибку, поэтому
+// его можно передавать между горутинами и хранить после дальнейшего оборачивания исходной ошибки
+type ErrorView struct {
+	// Text - текст Error() на момент снимка
+	Text string
+	// Code - внешний код цепочки (OutermostCode)
+	Code int
+	// Fingerprint - ключ группировки (Fingerprint)
+	Fingerprint string
+	// Levels - уровни цепочки от внешнего к внутреннему
+	Levels []LevelView
+}
+
+// LevelView - уровень цепочки в ErrorView. Для *Error заполнены атрибуты уровня, для сторонней ошибки - Message
+// (ошибки, которые она оборачивает, следуют отдельными уровнями), для MultiError и ошибок с Unwrap() []error - Errors
+type LevelView struct {
+	Op        string
+	Code      int
+	Place     string
+	Frames    []Frame
+	Goroutine uint64
+	Labels    map[string]string
+	Time      time.Time
+	Fields    map[string]any
+	// Remote и Service - уровень получен из другого компонента (см. Error.Remote)
+	Remote  bool
+	Service string
+
+	// Message - текст сторонней ошибки
+	Message string
+
+	// Errors - снимки ошибок MultiError
+	Errors  []ErrorView
+	Dropped int
+}
+
+// Snapshot возвращает снимок цепочки ошибок с операциями, кодами, кадрами и полями всех уровней.
+// Срезы и карты копируются, значения полей типов map[string]any и []any - рекурсивно. Предназначен для асинхронной
+// обработки ошибок вне обработки запроса. Для nil возвращается пустой снимок
+func Snapshot(err error) ErrorView {
+	if err == nil {
+		return ErrorView{}
+	}
+
+	res := ErrorView{
+		Text:        err.Error(),
+		Code:        OutermostCode(err),
+		Fingerprint: Fingerprint(err),
+	}
+
+	for err != nil {
+		switch v := err.(type) {
+		case *Error:
+			service, remote := v.Remote()
+			res.Levels = append(res.Levels, LevelView{
+				Op:        v.Operation(),
+				Code:      v.Code,
+				Place:     v.Place,
+				Frames:    append([]Frame(nil), v.Frames()...),
+				Goroutine: v.Goroutine,
+				Labels:    copyLabels(v.Labels),
+				Time:      v.Time,
+				Fields:    copyFields(v.Fields),
+				Remote:    remote,
+				Service:   service,
+			})
+			err = v.Err
+
+		case *MultiError:
+			level := LevelView{Dropped: v.Dropped}
+			for _, child := range v.Errors {
+				level.Errors = append(level.Errors, Snapshot(child))
+			}
+			res.Levels = append(res.Levels, level)
+			return res
+
+		default:
+			// сторонние обертки проходятся через Unwrap, чтобы снимок включал вложенные *Error
+			level := LevelView{Message: err.Error()}
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, child := range joined.Unwrap() {
+					if child != nil {
+						level.Errors = append(level.Errors, Snapshot(child))
+					}
+				}
+				res.Levels = append(res.Levels, level)
+				return res
+			}
+
+			res.Levels = append(res.Levels, level)
+			err = errors.Unwrap(err)
+		}
+	}
+
+	return res
+}
+
+// Ops возвращает непустые операции уровней от внешнего к внутреннему
+func (v ErrorView) Ops() []string {
+	var res []string
+	for _, l := range v.Levels {
+		if len(l.Op) > 0 {
+			res = append(res, l.Op)
+		}
+	}
+	return res
+}
+
+// Codes возвращает ненулевые коды уровней от внешнего к внутреннему
+func (v ErrorView) Codes() []int {
+	var res []int
+	for _, l := range v.Levels {
+		if l.Code != 0 {
+			res = append(res, l.Code)
+		}
+	}
+	return res
+}
+
+// Field возвращает значение поля с ближайшего к внешнему уровня, как Field
+func (v ErrorView) Field(key string) (any, bool) {
+	for _, l := range v.Levels {
+		if value, ok := l.Fields[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	res := make(map[string]string, len(labels))
+	for k, v := range labels {
+		res[k] = v
+	}
+	return res
+}
+
+func copyFields(fields map[string]any) map[string]any {
+	if fields == nil {
+		return nil
+	}
+
+	res := make(map[string]any, len(fields))
+	for k, v := range fields {
+		res[k] = copyValue(v)
+	}
+	return res
+}
+
+func copyValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		return copyFields(x)
+	case []any:
+		res := make([]any, len(x))
+		for i, item := range x {
+			res[i] = copyValue(item)
+		}
+		return res
+	}
+	return v
+}
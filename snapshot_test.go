@@ -0,0 +1,54 @@
+package nerr_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestSnapshotForeignWrapper(t *testing.T) {
+	inner := nerr.New("db.query", nerr.ErrTimeout, map[string]any{"table": "users"}, errors.New("io timeout"))
+	err := fmt.Errorf("load user: %w", inner)
+
+	v := nerr.Snapshot(err)
+
+	if got, want := v.Ops(), []string{"db.query"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ops = %v, want %v", got, want)
+	}
+	if got, want := v.Codes(), []int{nerr.ErrTimeout}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("codes = %v, want %v", got, want)
+	}
+	if v.Code != nerr.OutermostCode(err) {
+		t.Fatalf("code = %d, want OutermostCode %d", v.Code, nerr.OutermostCode(err))
+	}
+	if table, _ := v.Field("table"); table != "users" {
+		t.Fatalf("field table = %v", table)
+	}
+	if len(v.Levels) != 3 || v.Levels[0].Message != err.Error() || v.Levels[2].Message != "io timeout" {
+		t.Fatalf("levels = %+v", v.Levels)
+	}
+	if len(v.Levels[1].Frames) == 0 {
+		t.Fatal("frames of the nerr level are missing")
+	}
+}
+
+func TestSnapshotIndependent(t *testing.T) {
+	err := nerr.New("op", map[string]any{"nested": map[string]any{"id": 1}})
+	v := nerr.Snapshot(err)
+
+	var e *nerr.Error
+	errors.As(err, &e)
+	e.Fields["nested"].(map[string]any)["id"] = 2
+	e.Fields["added"] = true
+
+	nested, _ := v.Field("nested")
+	if nested.(map[string]any)["id"] != 1 {
+		t.Fatal("snapshot shares nested field values with the error")
+	}
+	if _, ok := v.Field("added"); ok {
+		t.Fatal("snapshot shares fields with the error")
+	}
+}
@@ -0,0 +1,157 @@
+package nerr
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	sourceRootsOnce sync.Once
+	// sourceRootsMu упорядочивает изменения списка, чтение выполняется без блокировок
+	sourceRootsMu sync.Mutex
+	// sourceRoots - неизменяемый список, заменяемый целиком
+	sourceRoots atomic.Pointer[[]sourceRoot]
+)
+
+type sourceRoot struct {
+	dir    string
+	prefix string
+}
+
+// TrimSourcePaths включает приведение путей к исходникам в Place к виду, который дает сборка с -trimpath.
+// Места возникновения ошибок в dev и release сборках после этого совпадают (см. WithTrimSourcePaths)
+func TrimSourcePaths(enable bool) {
+	Configure(WithTrimSourcePaths(enable))
+}
+
+// AddSourceRoot регистрирует каталог с исходниками модуля importPath на случай,
+// если его не удалось определить автоматически. Вызывать при инициализации
+func AddSourceRoot(dir, importPath string) {
+	dir = strings.TrimSuffix(slashPath(filepath.Clean(dir)), "/") + "/"
+	if len(importPath) > 0 {
+		importPath = strings.TrimSuffix(importPath, "/") + "/"
+	}
+
+	sourceRootsMu.Lock()
+	defer sourceRootsMu.Unlock()
+
+	old := loadSourceRoots()
+	list := make([]sourceRoot, 0, len(old)+1)
+	list = append(list, sourceRoot{dir: dir, prefix: importPath})
+	list = append(list, old...)
+	sourceRoots.Store(&list)
+}
+
+func loadSourceRoots() []sourceRoot {
+	sourceRootsOnce.Do(func() {
+		list := detectSourceRoots()
+		sourceRoots.Store(&list)
+	})
+
+	if list := sourceRoots.Load(); list != nil {
+		return *list
+	}
+	return nil
+}
+
+// normalizeSourcePath приводит путь к исходнику к виду slashPath, а при Config.TrimSourcePaths - к виду сборки с -trimpath
+func normalizeSourcePath(file string) string {
+	file = slashPath(file)
+	if !cfg().TrimSourcePaths {
+		return file
+	}
+
+	for _, r := range loadSourceRoots() {
+		if strings.HasPrefix(file, r.dir) {
+			return r.prefix + file[len(r.dir):]
+		}
+	}
+
+	// зависимости из кэша модулей и vendor
+	for _, marker := range []string{"/pkg/mod/", "/vendor/"} {
+		if i := strings.LastIndex(file, marker); i >= 0 {
+			return file[i+len(marker):]
+		}
+	}
+
+	return file
+}
+
+// slashPath приводит путь к одному виду на Windows и Unix, чтобы места возникновения, отпечатки и группировка ошибок
+// не зависели от платформы сборки: разделители "/", буква диска в верхнем регистре
+func slashPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' && p[0] >= 'a' && p[0] <= 'z' {
+		p = string(p[0]-'a'+'A') + p[1:]
+	}
+	return p
+}
+
+func detectSourceRoots() []sourceRoot {
+	var res []sourceRoot
+	if goroot := runtime.GOROOT(); len(goroot) > 0 {
+		res = append(res, sourceRoot{dir: path.Join(slashPath(goroot), "src") + "/"})
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok || len(info.Main.Path) == 0 {
+		return res
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "-trimpath" && s.Value == "true" {
+			// компилятор уже обрезал пути
+			return res
+		}
+	}
+
+	if dir := findModuleDir(info.Main.Path); len(dir) > 0 {
+		res = append(res, sourceRoot{dir: dir + "/", prefix: info.Main.Path + "/"})
+	}
+	return res
+}
+
+// findModuleDir ищет go.mod модуля modulePath вверх от рабочего каталога
+func findModuleDir(modulePath string) string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if readModulePath(filepath.Join(dir, "go.mod")) == modulePath {
+			return slashPath(dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func readModulePath(goMod string) string {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,41 @@
+package nerr_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestAddSourceRootConcurrent(t *testing.T) {
+	nerr.TrimSourcePaths(true)
+	defer nerr.TrimSourcePaths(false)
+
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(file)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			nerr.AddSourceRoot(filepath.Join(dir, "missing"+strconv.Itoa(i)), "example.com/missing")
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = nerr.New("op").Error()
+		}()
+	}
+	wg.Wait()
+
+	nerr.AddSourceRoot(dir, "example.com/nerr")
+
+	e := nerr.New("op").(*nerr.Error)
+	if !strings.Contains(e.Place, "(example.com/nerr/source_test.go:") {
+		t.Fatalf("Place = %q, want path under example.com/nerr", e.Place)
+	}
+}
@@ -0,0 +1,85 @@
+package nerr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+)
+
+func init() {
+	MapError(IsNotFoundSQL, ErrNotFound)
+	MapError(IsBadConn, ErrUnavailable)
+}
+
+// IsNotFoundSQL проверяет, что в цепочке есть sql.ErrNoRows
+func IsNotFoundSQL(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// IsBadConn проверяет, что в цепочке есть driver.ErrBadConn или sql.ErrConnDone
+func IsBadConn(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone)
+}
+
+// SqlCode возвращает SQLSTATE ошибки PostgreSQL из любого места цепочки. Поддерживаются ошибки всех драйверов
+// с методом SQLState() string (lib/pq, pgconn, pgx). Сравнение кодов с помощью github.com/jackc/pgerrcode
+func SqlCode(err error) string {
+	var sqlErr interface{ SQLState() string }
+	if errors.As(err, &sqlErr) {
+		return sqlErr.SQLState()
+	}
+	return ""
+}
+
+// Коды SQLSTATE, используемые предикатами
+const (
+	sqlStateForeignKeyViolation  = "23503"
+	sqlStateUniqueViolation      = "23505"
+	sqlStateCheckViolation       = "23514"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+	sqlClassConnectionException  = "08"
+)
+
+// IsUniqueViolation проверяет нарушение уникальности (23505)
+func IsUniqueViolation(err error) bool {
+	return SqlCode(err) == sqlStateUniqueViolation
+}
+
+// IsForeignKeyViolation проверяет нарушение внешнего ключа (23503)
+func IsForeignKeyViolation(err error) bool {
+	return SqlCode(err) == sqlStateForeignKeyViolation
+}
+
+// IsCheckViolation проверяет нарушение ограничения CHECK (23514)
+func IsCheckViolation(err error) bool {
+	return SqlCode(err) == sqlStateCheckViolation
+}
+
+// IsSerializationFailure проверяет конфликт сериализации транзакций (40001)
+func IsSerializationFailure(err error) bool {
+	return SqlCode(err) == sqlStateSerializationFailure
+}
+
+// IsRetryableSQL проверяет, что транзакцию можно повторить: конфликт сериализации (40001), взаимоблокировка (40P01)
+// или сбой соединения (класс 08, driver.ErrBadConn, ошибки драйвера с SafeToRetry() == true)
+func IsRetryableSQL(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch code := SqlCode(err); {
+	case code == sqlStateSerializationFailure, code == sqlStateDeadlockDetected:
+		return true
+	case strings.HasPrefix(code, sqlClassConnectionException):
+		return true
+	}
+
+	var safeErr interface{ SafeToRetry() bool }
+	if errors.As(err, &safeErr) && safeErr.SafeToRetry() {
+		return true
+	}
+
+	return IsBadConn(err)
+}
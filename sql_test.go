@@ -0,0 +1,96 @@
+//go:build go1.20
+
+// errors.As обходит MultiError (Unwrap() []error) начиная с Go 1.20
+
+package nerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+// sqlStateError - ошибка драйвера с методом SQLState, как у lib/pq и pgconn
+type sqlStateError struct {
+	code string
+}
+
+func (e *sqlStateError) Error() string    { return "sqlstate " + e.code }
+func (e *sqlStateError) SQLState() string { return e.code }
+
+func TestSqlCode(t *testing.T) {
+	unique := &sqlStateError{code: "23505"}
+	deadlock := &sqlStateError{code: "40P01"}
+
+	collected := func(errs ...error) error {
+		var c nerr.Collector
+		for _, err := range errs {
+			c.Add(err)
+		}
+		return c.Err()
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"not sql", errors.New("plain"), ""},
+		{"no rows", nerr.New("op", sql.ErrNoRows), ""},
+		{"direct", unique, "23505"},
+		{"nerr", nerr.New("insert", unique), "23505"},
+		{"nerr nested", nerr.New("service", nerr.New("repo", nerr.New("insert", unique))), "23505"},
+		{"fmt", fmt.Errorf("insert: %w", unique), "23505"},
+		{"fmt over nerr", fmt.Errorf("handler: %w", nerr.New("service", nerr.New("insert", unique))), "23505"},
+		{"nerr over fmt", nerr.New("service", fmt.Errorf("repo: %w", fmt.Errorf("insert: %w", unique))), "23505"},
+		{"nerr code", nerr.New("service", nerr.ErrConflict, nerr.New("insert", unique)), "23505"},
+		{"multi", &nerr.MultiError{Errors: []error{errors.New("other"), nerr.New("insert", unique)}}, "23505"},
+		{"multi first", &nerr.MultiError{Errors: []error{nerr.New("a", deadlock), nerr.New("b", unique)}}, "40P01"},
+		{"collector", nerr.New("batch", collected(errors.New("other"), fmt.Errorf("row 2: %w", nerr.New("insert", unique)))), "23505"},
+		{"join", nerr.New("batch", errors.Join(errors.New("other"), nerr.New("repo", nerr.New("insert", deadlock)))), "40P01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nerr.SqlCode(tt.err); got != tt.want {
+				t.Fatalf("SqlCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSqlPredicates(t *testing.T) {
+	wrap := func(code string) error {
+		return fmt.Errorf("handler: %w", nerr.New("service", nerr.New("insert", &sqlStateError{code: code})))
+	}
+
+	tests := []struct {
+		name string
+		fn   func(error) bool
+		err  error
+		want bool
+	}{
+		{"unique", nerr.IsUniqueViolation, wrap("23505"), true},
+		{"unique other", nerr.IsUniqueViolation, wrap("23503"), false},
+		{"foreign key", nerr.IsForeignKeyViolation, wrap("23503"), true},
+		{"check", nerr.IsCheckViolation, wrap("23514"), true},
+		{"serialization", nerr.IsSerializationFailure, wrap("40001"), true},
+		{"retryable serialization", nerr.IsRetryableSQL, wrap("40001"), true},
+		{"retryable deadlock", nerr.IsRetryableSQL, wrap("40P01"), true},
+		{"retryable connection", nerr.IsRetryableSQL, wrap("08006"), true},
+		{"retryable unique", nerr.IsRetryableSQL, wrap("23505"), false},
+		{"retryable nil", nerr.IsRetryableSQL, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.err); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package nerr
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Frame - кадр стека вызовов
+type Frame struct {
+	Function string `json:"function" msgpack:"function"`
+	File     string `json:"file" msgpack:"file"`
+	Line     int    `json:"line" msgpack:"line"`
+}
+
+// RemoteFrame - разделитель в Stack между локальными кадрами и кадрами ошибки, полученной из другого компонента (см. MergeTrace)
+var RemoteFrame = Frame{Function: "--- remote ---"}
+
+func (f Frame) String() string {
+	if f == RemoteFrame {
+		return f.Function
+	}
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// ParseFrame разбирает строку в формате Frame.String()
+func ParseFrame(s string) (Frame, bool) {
+	open := strings.LastIndex(s, " (")
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return Frame{}, false
+	}
+
+	location := s[open+2 : len(s)-1]
+	colon := strings.LastIndex(location, ":")
+	if colon < 0 {
+		return Frame{}, false
+	}
+
+	line, err := strconv.Atoi(location[colon+1:])
+	if err != nil {
+		return Frame{}, false
+	}
+
+	return Frame{
+		Function: s[:open],
+		File:     location[:colon],
+		Line:     line,
+	}, true
+}
+
+// Frames возвращает стек места возникновения ошибки: Stack, если он сохранен, иначе кадр из Place
+func (e *Error) Frames() []Frame {
+	if len(e.Stack) > 0 {
+		return e.Stack
+	}
+
+	if f, ok := ParseFrame(e.Place); ok {
+		return []Frame{f}
+	}
+
+	return nil
+}
+
+// ownFrames возвращает кадры стека уровня, которых нет в стеке ближайшей вложенной *Error, и количество общих кадров.
+// При многократном оборачивании в одном стеке вызовов стек внешнего уровня - хвост стека вложенного
+func ownFrames(e *Error) (own []Frame, shared int) {
+	inner, ok := e.Err.(*Error)
+	if !ok || len(inner.Stack) == 0 || crossesGoroutine(e) || hasRemoteFrames(e.Stack) || !sameOrigin(e, inner) {
+		return e.Stack, 0
+	}
+
+	for shared < len(e.Stack) && shared < len(inner.Stack) &&
+		e.Stack[len(e.Stack)-1-shared] == inner.Stack[len(inner.Stack)-1-shared] {
+		shared++
+	}
+	return e.Stack[:len(e.Stack)-shared], shared
+}
+
+// MergeTrace объединяет стеки при повторном оборачивании ошибки, полученной из другого компонента (например после
+// разбора Envelope). Возвращает копию outer, Stack которой - кадры outer, RemoteFrame и кадры первой *Error из inner.
+// Если outer не содержит вложенной ошибки, ею становится inner
+func MergeTrace(outer, inner error) error {
+	if outer == nil {
+		return inner
+	}
+	if inner == nil {
+		return outer
+	}
+
+	res := cloneOrWrap(outer)
+	if res.Err == nil {
+		res.Err = inner
+	}
+
+	var remote *Error
+	if !errors.As(inner, &remote) {
+		return res
+	}
+
+	local := res.Frames()
+	remoteFrames := remote.Frames()
+	stack := make([]Frame, 0, len(local)+1+len(remoteFrames))
+	stack = append(stack, local...)
+	stack = append(stack, RemoteFrame)
+	res.Stack = append(stack, remoteFrames...)
+
+	return res
+}
+
+func hasRemoteFrames(stack []Frame) bool {
+	for _, f := range stack {
+		if f == RemoteFrame {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStackDepth задает количество кадров стека, сохраняемых в Error.Stack.
+// При depth <= 0 (по умолчанию) запоминается только Place (см. WithStackDepth)
+func SetStackDepth(depth int) {
+	Configure(WithStackDepth(depth))
+}
+
+// SkipPackages исключает из Place и Stack кадры функций, полное имя которых начинается с одного из префиксов
+// (например "github.com/org/app/middleware.") (см. WithSkipPackages)
+func SkipPackages(prefixes ...string) {
+	Configure(WithSkipPackages(prefixes...))
+}
+
+// Caller возвращает кадр стека в том же виде, что и Place ошибок: с учетом SkipPackages и TrimSourcePaths.
+// skip - как у runtime.Caller: 0 - функция, вызвавшая Caller. Если кадр недоступен, возвращается пустой Frame
+func Caller(skip int) Frame {
+	if frames := callersDepth(skip+1, 1); len(frames) > 0 {
+		return frames[0]
+	}
+	return Frame{}
+}
+
+func skipFrame(skipPackages []string, function string) bool {
+	for _, p := range skipPackages {
+		if strings.HasPrefix(function, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// callers возвращает стек глубиной Config.StackDepth, начиная с уровня skip относительно вызвавшей функции
+// (в терминах runtime.Caller)
+func callers(skip int) []Frame {
+	return callersDepth(skip+1, cfg().StackDepth)
+}
+
+// callersDepth возвращает не более depth кадров стека (не менее одного), начиная с уровня skip
+func callersDepth(skip, depth int) []Frame {
+	c := cfg()
+
+	if depth <= 0 {
+		depth = 1
+	}
+
+	// запас на отфильтрованные кадры
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		// стек недоступен (например в TinyGo): Place и Stack остаются пустыми
+		return nil
+	}
+
+	res := make([]Frame, 0, depth)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !skipFrame(c.SkipPackages, f.Function) {
+			res = append(res, Frame{
+				Function: f.Function,
+				File:     normalizeSourcePath(f.File),
+				Line:     f.Line,
+			})
+			if len(res) == depth {
+				break
+			}
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return res
+}
+
+// defaultWithStackDepth - глубина стека WithStack, если Config.StackDepth не задан
+const defaultWithStackDepth = 32
+
+// WithStack оборачивает стороннюю ошибку уровнем, содержащим только стек места вызова (см. Frames), не меняя ее текст,
+// операцию и код. Глубина стека - Config.StackDepth или 32, если он не задан. *Error возвращается без изменений
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+
+	depth := cfg().StackDepth
+	if depth <= 0 {
+		depth = defaultWithStackDepth
+	}
+
+	// Place не задается, чтобы в тексте ошибки не появился source
+	e := &Error{Err: err, Stack: callersDepth(1, depth)}
+
+	runHooks(e)
+	return e
+}
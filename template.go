@@ -0,0 +1,65 @@
+package nerr
+
+import (
+	"strings"
+	"text/template"
+)
+
+// TemplateData - данные, доступные в шаблоне TemplateFormatter
+type TemplateData struct {
+	// Op - операция уровня
+	Op string
+	// Ops - операции всех уровней цепочки
+	Ops []string
+	// Code - код ошибки (OutermostCode)
+	Code int
+	// Source - место возникновения, как в стандартном тексте
+	Source string
+	// Cause - текст вложенной ошибки
+	Cause string
+	// Fields - поля всех уровней (AllFields)
+	Fields map[string]any
+}
+
+// TemplateFormatter создает Config.Formatter из шаблона text/template:
+//
+//	f, err := nerr.TemplateFormatter(`{{.Op}} [{{.Code}}] {{.Source}}{{if .Cause}}: {{.Cause}}{{end}}`)
+//	nerr.Configure(nerr.WithFormatter(f))
+//
+// При ошибке выполнения шаблона используется стандартный текст
+func TemplateFormatter(text string) (func(e *Error) string, error) {
+	tmpl, err := template.New("nerr").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(e *Error) string {
+		data := TemplateData{
+			Op:     e.Operation(),
+			Ops:    Ops(e),
+			Code:   OutermostCode(e),
+			Fields: AllFields(e),
+		}
+		if source, ok := lastTraceLine(e); ok {
+			data.Source = source
+		}
+		if e.Err != nil {
+			data.Cause = causeText(e.Err)
+		}
+
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return FormatDefault(e)
+		}
+		return b.String()
+	}, nil
+}
+
+// MustTemplateFormatter аналог TemplateFormatter, вызывающий панику при ошибке разбора шаблона
+func MustTemplateFormatter(text string) func(e *Error) string {
+	f, err := TemplateFormatter(text)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,57 @@
+package nerr
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+)
+
+// Поля ошибки, оборачивающей ошибку проверки сертификата
+const (
+	FieldTLSSubject  = "tls.subject"
+	FieldTLSIssuer   = "tls.issuer"
+	FieldTLSNotAfter = "tls.not_after"
+	FieldTLSHost     = "tls.host"
+)
+
+// IsCertificateInvalid проверяет, что в цепочке есть ошибка проверки сертификата: недействительный
+// (в том числе просроченный) сертификат или сертификат, подписанный неизвестным центром
+func IsCertificateInvalid(err error) bool {
+	var invalidErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	return errors.As(err, &invalidErr) || errors.As(err, &authorityErr)
+}
+
+// IsCertificateHostname проверяет, что в цепочке есть ошибка несоответствия сертификата имени хоста
+func IsCertificateHostname(err error) bool {
+	var hostnameErr x509.HostnameError
+	return errors.As(err, &hostnameErr)
+}
+
+// IsTLSHandshakeTimeout проверяет, что истек таймаут TLS рукопожатия. Стандартная библиотека
+// не экспортирует тип этой ошибки, поэтому она определяется по тексту сетевой ошибки с таймаутом
+func IsTLSHandshakeTimeout(err error) bool {
+	return IsNetTimeout(err) && strings.Contains(err.Error(), "TLS handshake timeout")
+}
+
+// addCertificateFields добавляет в ошибку сведения о сертификате, если она непосредственно
+// (без промежуточных *Error) оборачивает ошибку его проверки
+func addCertificateFields(e *Error) {
+	var cert *x509.Certificate
+
+	if v, ok := directCause[x509.CertificateInvalidError](e); ok {
+		cert = v.Cert
+	} else if v, ok := directCause[x509.UnknownAuthorityError](e); ok {
+		cert = v.Cert
+	} else if v, ok := directCause[x509.HostnameError](e); ok {
+		cert = v.Certificate
+		setField(e, FieldTLSHost, v.Host)
+	}
+	if cert == nil {
+		return
+	}
+
+	setField(e, FieldTLSSubject, cert.Subject.String())
+	setField(e, FieldTLSIssuer, cert.Issuer.String())
+	setField(e, FieldTLSNotAfter, cert.NotAfter)
+}
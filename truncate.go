@@ -0,0 +1,41 @@
+package nerr
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// causeText возвращает текст вложенной ошибки. Текст сторонних ошибок очищается (Config.Sanitize)
+// и обрезается до Config.MaxMessageLength
+func causeText(err error) string {
+	switch err.(type) {
+	case *Error, *MultiError:
+		return err.Error()
+	}
+
+	if _, ok := err.(fmt.Formatter); ok {
+		return messageText(fmt.Sprintf("%v", err))
+	}
+	return messageText(err.Error())
+}
+
+// messageText возвращает текст сторонней ошибки для вывода: очищенный и обрезанный
+func messageText(s string) string {
+	return truncateMessage(SanitizeString(s))
+}
+
+// truncateMessage обрезает текст до Config.MaxMessageLength по границе символа и добавляет отметку об обрезке
+func truncateMessage(s string) string {
+	limit := cfg().MaxMessageLength
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + "… (truncated " + strconv.Itoa(len(s)-cut) + " bytes)"
+}
@@ -0,0 +1,15 @@
+module github.com/n-r-w/nerr/twirperr
+
+go 1.19
+
+require (
+	github.com/n-r-w/nerr v0.0.0-20261016022808-a66b0c6d80ab
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require (
+	github.com/n-r-w/eno v1.0.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+)
+
+replace github.com/n-r-w/nerr => ..
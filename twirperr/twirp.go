@@ -0,0 +1,107 @@
+// Package twirperr - преобразование ошибок nerr в ошибки Twirp и обратно
+package twirperr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/n-r-w/nerr"
+	"github.com/twitchtv/twirp"
+)
+
+// MetaCode - ключ метаданных Twirp с исходным кодом nerr
+const MetaCode = "nerr_code"
+
+var (
+	mu        sync.RWMutex
+	toTwirp   = map[int]twirp.ErrorCode{}
+	fromTwirp = map[twirp.ErrorCode]int{}
+)
+
+func init() {
+	RegisterCode(nerr.ErrValidation, twirp.InvalidArgument)
+	RegisterCode(nerr.ErrNotFound, twirp.NotFound)
+	RegisterCode(nerr.ErrTimeout, twirp.DeadlineExceeded)
+	RegisterCode(nerr.ErrCanceled, twirp.Canceled)
+	RegisterCode(nerr.ErrUnavailable, twirp.Unavailable)
+	RegisterCode(nerr.ErrConflict, twirp.AlreadyExists)
+	RegisterCode(nerr.ErrRateLimited, twirp.ResourceExhausted)
+	RegisterCode(nerr.ErrPermissionDenied, twirp.PermissionDenied)
+}
+
+// RegisterCode задает соответствие кода nerr коду Twirp. Первый зарегистрированный код nerr
+// используется и для обратного преобразования. Вызывать при инициализации
+func RegisterCode(code int, twirpCode twirp.ErrorCode) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	toTwirp[code] = twirpCode
+	if _, ok := fromTwirp[twirpCode]; !ok {
+		fromTwirp[twirpCode] = code
+	}
+}
+
+// ToTwirp преобразует ошибку в twirp.Error. Поля ошибки, кроме внутренних (nerr.PublicFields), передаются в метаданных
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+
+	var twerr twirp.Error
+	if errors.As(err, &twerr) {
+		return twerr
+	}
+
+	code := nerr.OutermostCode(err)
+
+	mu.RLock()
+	twirpCode, ok := toTwirp[code]
+	mu.RUnlock()
+	if !ok {
+		twirpCode = twirp.Internal
+	}
+
+	twerr = twirp.NewError(twirpCode, err.Error())
+	if code != 0 {
+		twerr = twerr.WithMeta(MetaCode, strconv.Itoa(code))
+	}
+
+	for k, v := range nerr.PublicFields(err) {
+		if k != MetaCode {
+			twerr = twerr.WithMeta(k, fmt.Sprint(v))
+		}
+	}
+
+	return twerr
+}
+
+// FromTwirp преобразует ошибку Twirp, полученную клиентом, в *nerr.Error с кодом и полями из метаданных.
+// Ошибки, не являющиеся twirp.Error, возвращаются без изменений
+func FromTwirp(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var twerr twirp.Error
+	if !errors.As(err, &twerr) {
+		return err
+	}
+
+	code, convErr := strconv.Atoi(twerr.Meta(MetaCode))
+	if convErr != nil {
+		mu.RLock()
+		code = fromTwirp[twerr.Code()]
+		mu.RUnlock()
+	}
+
+	fields := make(map[string]any)
+	for k, v := range twerr.MetaMap() {
+		if k != MetaCode {
+			fields[k] = v
+		}
+	}
+
+	return nerr.New(nerr.Skip(1), code, fields, errors.New(twerr.Msg()))
+}
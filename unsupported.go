@@ -0,0 +1,37 @@
+package nerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldUnsupportedValue - значение, которое не поддерживается (см. Unsupported)
+const FieldUnsupportedValue = "unsupported.value"
+
+func init() {
+	MapError(func(err error) bool { return errors.Is(err, errUnsupported) }, ErrUnsupported)
+}
+
+// NotImplemented создает ошибку операции op с кодом ErrNotImplemented (HTTP 501, gRPC Unimplemented)
+// для заглушек и нереализованных веток
+func NotImplemented(op string) error {
+	return New(Skip(1), op, ErrNotImplemented)
+}
+
+// Unsupported создает ошибку операции op с кодом ErrUnsupported (HTTP 400, gRPC InvalidArgument) для значения value,
+// которое операция не поддерживает (формат, тип, вариант перечисления). Значение сохраняется в поле
+// FieldUnsupportedValue, вложенная ошибка - errors.ErrUnsupported (до Go 1.21 - собственная ошибка пакета)
+func Unsupported(op string, value any) error {
+	return New(Skip(1), op, ErrUnsupported, map[string]any{FieldUnsupportedValue: value},
+		fmt.Errorf("%w: %v", errUnsupported, value))
+}
+
+// IsNotImplemented проверяет, что в цепочке есть код ErrNotImplemented
+func IsNotImplemented(err error) bool {
+	return HasAnyCode(err, ErrNotImplemented)
+}
+
+// IsUnsupported проверяет, что в цепочке есть код ErrUnsupported или errors.ErrUnsupported
+func IsUnsupported(err error) bool {
+	return HasAnyCode(err, ErrUnsupported) || errors.Is(err, errUnsupported)
+}
@@ -0,0 +1,8 @@
+//go:build go1.21
+
+package nerr
+
+import "errors"
+
+// errUnsupported - причина ошибок Unsupported
+var errUnsupported = errors.ErrUnsupported
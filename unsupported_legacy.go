@@ -0,0 +1,8 @@
+//go:build !go1.21
+
+package nerr
+
+import "errors"
+
+// errUnsupported - причина ошибок Unsupported. errors.ErrUnsupported появилась в Go 1.21
+var errUnsupported = errors.New("unsupported operation")
@@ -0,0 +1,130 @@
+package nerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError - ошибка проверки одного поля
+type ValidationError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Validation создает ошибку проверки поля field по правилу rule
+func Validation(field, rule, msg string) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Rule:    rule,
+		Message: msg,
+	}
+}
+
+func (v *ValidationError) Error() string {
+	if len(v.Rule) > 0 {
+		return fmt.Sprintf("%s: %s (%s)", v.Field, v.Message, v.Rule)
+	}
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// ValidationErrors - набор ошибок проверки полей. В JSON представляется как {поле: [сообщения]}
+type ValidationErrors []*ValidationError
+
+// Add добавляет ошибку проверки поля
+func (v *ValidationErrors) Add(field, rule, msg string) {
+	*v = append(*v, Validation(field, rule, msg))
+}
+
+// Merge добавляет ошибки проверки вложенной структуры в исходном порядке, сохраняя правила и дополняя пути полей
+// префиксом prefix (см. WithPathPrefix)
+func (v *ValidationErrors) Merge(prefix string, nested error) {
+	*v = append(*v, validationList(nested).WithPathPrefix(prefix)...)
+}
+
+// WithPathPrefix возвращает копию ошибок, в которой пути полей вложены в prefix:
+// "city" -> "address.city", "[3].price" -> "items[3].price"
+func (v ValidationErrors) WithPathPrefix(prefix string) ValidationErrors {
+	res := make(ValidationErrors, 0, len(v))
+	for _, e := range v {
+		res = append(res, &ValidationError{
+			Field:   JoinPath(prefix, e.Field),
+			Rule:    e.Rule,
+			Message: e.Message,
+		})
+	}
+	return res
+}
+
+// JoinPath соединяет путь к полю с вложенным путем
+func JoinPath(prefix, field string) string {
+	switch {
+	case len(prefix) == 0:
+		return field
+	case len(field) == 0:
+		return prefix
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	default:
+		return prefix + "." + field
+	}
+}
+
+// IndexPath возвращает путь к элементу массива: IndexPath("items", 3) == "items[3]"
+func IndexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func (v ValidationErrors) Error() string {
+	texts := make([]string, 0, len(v))
+	for _, e := range v {
+		texts = append(texts, e.Error())
+	}
+	return strings.Join(texts, "; ")
+}
+
+// Map возвращает сообщения, сгруппированные по полям
+func (v ValidationErrors) Map() map[string][]string {
+	res := make(map[string][]string, len(v))
+	for _, e := range v {
+		res[e.Field] = append(res[e.Field], e.Message)
+	}
+	return res
+}
+
+func (v ValidationErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Map())
+}
+
+// Err возвращает nil, если ошибок нет, иначе *Error с кодом ErrValidation
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return New(Skip(1), ErrValidation, v)
+}
+
+// validationList извлекает из цепочки ошибки проверки полей в исходном порядке
+func validationList(err error) ValidationErrors {
+	var list ValidationErrors
+	if errors.As(err, &list) {
+		return list
+	}
+
+	var single *ValidationError
+	if errors.As(err, &single) {
+		return ValidationErrors{single}
+	}
+
+	return nil
+}
+
+// ValidationFields извлекает из цепочки ошибки проверки полей, сгруппированные по полям
+func ValidationFields(err error) map[string][]string {
+	if list := validationList(err); len(list) > 0 {
+		return list.Map()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package nerr_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestValidationErrorsMerge(t *testing.T) {
+	var item nerr.ValidationErrors
+	item.Add("price", "gt", "must be positive")
+	item.Add("name", "required", "is required")
+	item.Add("price", "max", "too large")
+
+	var address nerr.ValidationErrors
+	address.Add("city", "required", "is required")
+
+	var v nerr.ValidationErrors
+	v.Add("id", "uuid", "invalid id")
+	v.Merge(nerr.IndexPath("items", 3), item.Err())
+	v.Merge("address", address)
+	v.Merge("ignored", nil)
+
+	want := []nerr.APIDetail{
+		{Field: "id", Rule: "uuid", Message: "invalid id"},
+		{Field: "items[3].price", Rule: "gt", Message: "must be positive"},
+		{Field: "items[3].name", Rule: "required", Message: "is required"},
+		{Field: "items[3].price", Rule: "max", Message: "too large"},
+		{Field: "address.city", Rule: "required", Message: "is required"},
+	}
+
+	// порядок и правила не зависят от запуска
+	for i := 0; i < 20; i++ {
+		env := nerr.NewAPIEnvelope(v.Err())
+		if !reflect.DeepEqual(env.Details, want) {
+			t.Fatalf("details = %+v, want %+v", env.Details, want)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{"address.city":["is required"],"id":["invalid id"],"items[3].name":["is required"],"items[3].price":["must be positive","too large"]}`
+	if string(data) != wantJSON {
+		t.Fatalf("JSON = %s, want %s", data, wantJSON)
+	}
+
+	wantText := "id: invalid id (uuid); items[3].price: must be positive (gt); items[3].name: is required (required); " +
+		"items[3].price: too large (max); address.city: is required (required)"
+	if v.Error() != wantText {
+		t.Fatalf("Error() = %q, want %q", v.Error(), wantText)
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		prefix, field, want string
+	}{
+		{"", "city", "city"},
+		{"address", "", "address"},
+		{"address", "city", "address.city"},
+		{"items", "[3].price", "items[3].price"},
+		{nerr.IndexPath("items", 3), "price", "items[3].price"},
+	}
+
+	for _, tt := range tests {
+		if got := nerr.JoinPath(tt.prefix, tt.field); got != tt.want {
+			t.Fatalf("JoinPath(%q, %q) = %q, want %q", tt.prefix, tt.field, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package nerr
+
+// WithValue прикрепляет к ошибке произвольное значение (например конфликтующую сущность), которое можно получить
+// с помощью Value. Значения не попадают в текст и сериализованное представление ошибки.
+// Для *Error возвращается копия с добавленным значением, сторонняя ошибка оборачивается
+func WithValue(err error, payload any) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+
+	e.values = append(e.values, payload)
+	return e
+}
+
+// Value возвращает значение типа T, прикрепленное WithValue, с ближайшего к внешнему уровня цепочки.
+// На одном уровне приоритет у значения, прикрепленного последним
+func Value[T any](err error) (T, bool) {
+	for err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			break
+		}
+
+		for i := len(e.values) - 1; i >= 0; i-- {
+			if v, ok := e.values[i].(T); ok {
+				return v, true
+			}
+		}
+		err = e.Err
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,122 @@
+package nerr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поля источника ошибки в конвейере обработки (см. WithWorker)
+const (
+	// FieldStage - этап конвейера
+	FieldStage = "stage"
+	// FieldWorker - номер обработчика этапа
+	FieldWorker = "worker"
+)
+
+// WithWorker указывает этап конвейера и номер обработчика, в котором возникла ошибка (поля FieldStage и FieldWorker).
+// Пустой stage и отрицательный worker не задаются. Collector группирует ошибки по ним (см. Collector.Groups).
+// Для *Error возвращается копия, сторонняя ошибка оборачивается
+func WithWorker(err error, stage string, worker int) error {
+	if err == nil {
+		return nil
+	}
+
+	e := cloneOrWrap(err)
+
+	if len(stage) > 0 {
+		setField(e, FieldStage, stage)
+	}
+	if worker >= 0 {
+		setField(e, FieldWorker, worker)
+	}
+	return e
+}
+
+// Worker возвращает этап и номер обработчика, заданные WithWorker. Незаданный номер - -1
+func Worker(err error) (stage string, worker int, ok bool) {
+	worker = -1
+
+	if v, found := Field(err, FieldStage); found {
+		stage, _ = v.(string)
+	}
+	if v, found := Field(err, FieldWorker); found {
+		switch n := v.(type) {
+		case int:
+			worker = n
+		case float64:
+			// после разбора Envelope
+			worker = int(n)
+		}
+	}
+
+	return stage, worker, len(stage) > 0 || worker >= 0
+}
+
+// WorkerGroup - количество ошибок Collector с одинаковыми этапом и номером обработчика
+type WorkerGroup struct {
+	Stage string
+	// Worker - номер обработчика или -1
+	Worker int
+	Count  int
+	// Share - доля группы среди всех добавленных ошибок
+	Share float64
+}
+
+// String возвращает группу в виде "stage=resize worker=7: 12 (40%)". Ошибки без этапа и обработчика - "unattributed"
+func (g WorkerGroup) String() string {
+	return fmt.Sprintf("%s: %d (%.0f%%)", g.label(), g.Count, g.Share*100)
+}
+
+func (g WorkerGroup) label() string {
+	var parts []string
+	if len(g.Stage) > 0 {
+		parts = append(parts, "stage="+g.Stage)
+	}
+	if g.Worker >= 0 {
+		parts = append(parts, "worker="+strconv.Itoa(g.Worker))
+	}
+	if len(parts) == 0 {
+		return "unattributed"
+	}
+	return strings.Join(parts, " ")
+}
+
+type workerKey struct {
+	stage  string
+	worker int
+}
+
+// attributed проверяет, что хотя бы одна группа имеет этап или номер обработчика
+func attributed(counts map[workerKey]int) bool {
+	for k := range counts {
+		if len(k.stage) > 0 || k.worker >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// workerGroups упорядочивает группы по убыванию количества ошибок, затем по этапу и номеру обработчика
+func workerGroups(counts map[workerKey]int) []WorkerGroup {
+	var total int
+	for _, n := range counts {
+		total += n
+	}
+
+	res := make([]WorkerGroup, 0, len(counts))
+	for k, n := range counts {
+		res = append(res, WorkerGroup{Stage: k.stage, Worker: k.worker, Count: n, Share: float64(n) / float64(total)})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		if res[i].Stage != res[j].Stage {
+			return res[i].Stage < res[j].Stage
+		}
+		return res[i].Worker < res[j].Worker
+	})
+	return res
+}
@@ -0,0 +1,75 @@
+package nerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/n-r-w/nerr"
+)
+
+func TestCollectorWorkerGroups(t *testing.T) {
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		limit      int
+		errs       []error
+		wantGroups string
+	}{
+		{
+			name: "attributed",
+			errs: []error{
+				nerr.WithWorker(boom, "resize", 7),
+				nerr.WithWorker(boom, "resize", 7),
+				nerr.WithWorker(boom, "upload", -1),
+			},
+			wantGroups: "groups: stage=resize worker=7: 2 (67%), stage=upload: 1 (33%)",
+		},
+		{
+			name:  "dropped",
+			limit: 1,
+			errs: []error{
+				nerr.WithWorker(boom, "resize", 1),
+				nerr.WithWorker(boom, "resize", 2),
+				boom,
+				boom,
+			},
+			wantGroups: "groups: unattributed: 2 (50%), stage=resize worker=1: 1 (25%), stage=resize worker=2: 1 (25%)",
+		},
+		{
+			name: "unattributed",
+			errs: []error{boom, boom},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c nerr.Collector
+			c.SetLimit(tt.limit)
+			for _, err := range tt.errs {
+				c.Add(err)
+			}
+
+			var m *nerr.MultiError
+			if !errors.As(c.Err(), &m) {
+				t.Fatalf("want MultiError, got %#v", c.Err())
+			}
+
+			text := m.Error()
+			if len(tt.wantGroups) == 0 {
+				if len(m.Groups) > 0 || strings.Contains(text, "groups:") {
+					t.Fatalf("unexpected groups in %q", text)
+				}
+				return
+			}
+
+			if !strings.HasSuffix(text, "; "+tt.wantGroups) {
+				t.Fatalf("Error() = %q, want suffix %q", text, tt.wantGroups)
+			}
+			if pretty := nerr.Pretty(m); !strings.Contains(pretty, tt.wantGroups) {
+				t.Fatalf("Pretty() = %q, want %q", pretty, tt.wantGroups)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package nerr
+
+import (
+	"context"
+
+	"github.com/n-r-w/eno"
+)
+
+// WrapOnce аналог New, который не создает новый уровень, если оборачиваемая ошибка - *Error, созданная в той же
+// функции или с той же операцией (например, при повторном оборачивании в defer). В этом случае возвращается
+// копия ошибки, дополненная операциями, кодом и контекстом из args. Если коды различаются, создается новый уровень.
+// В отличие от New, если среди args есть ошибка и все ошибки равны nil, возвращается nil, поэтому WrapOnce можно
+// вызывать в defer без проверки: defer func() { err = nerr.WrapOnce("op", err) }()
+func WrapOnce(args ...any) error {
+	if wrapsNil(args) {
+		return nil
+	}
+
+	if inner := wrapOnceTarget(args); inner != nil {
+		if res, ok := mergeArgs(inner, args); ok {
+			return res
+		}
+	}
+
+	e := newError(2, args)
+	if e == nil {
+		return nil
+	}
+
+	runHooks(e)
+	return e
+}
+
+// wrapsNil сообщает, что args содержат оборачиваемую ошибку и все такие ошибки равны nil
+func wrapsNil(args []any) bool {
+	var found bool
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			found = true
+		case error:
+			if !isNilError(v) {
+				return false
+			}
+			found = true
+		case []error:
+			for _, err := range v {
+				if err != nil && !isNilError(err) {
+					return false
+				}
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// wrapOnceTarget возвращает *Error из args, если она создана в функции, вызвавшей WrapOnce, или с той же операцией
+func wrapOnceTarget(args []any) *Error {
+	var inner *Error
+	for _, arg := range args {
+		if e, ok := arg.(*Error); ok && e != nil {
+			inner = e
+			break
+		}
+	}
+	if inner == nil {
+		return nil
+	}
+
+	for _, arg := range args {
+		if op, ok := arg.(string); ok && len(op) > 0 && op == inner.Operation() {
+			return inner
+		}
+	}
+
+	// уровень 2 - функция, вызвавшая WrapOnce
+	caller := callersDepth(2, 1)
+	place, ok := ParseFrame(inner.Place)
+	if ok && len(caller) > 0 && caller[0].Function == place.Function {
+		return inner
+	}
+	return nil
+}
+
+// mergeArgs возвращает копию inner, дополненную аргументами New, или false, если их нельзя объединить
+func mergeArgs(inner *Error, args []any) (*Error, bool) {
+	res := inner.Clone()
+
+	for _, arg := range args {
+		var code int
+		switch v := arg.(type) {
+		case *Error:
+			if v != inner {
+				return nil, false
+			}
+			continue
+		case string:
+			prepareProperty(res, v)
+			continue
+		case context.Context:
+			prepareContext(res, v)
+			continue
+		case Skip:
+			continue
+		case eno.ErrNo:
+			code = int(v)
+		default:
+			var ok bool
+			if code, ok = integerCode(v); !ok {
+				return nil, false
+			}
+		}
+
+		if res.Code != 0 && res.Code != code {
+			return nil, false
+		}
+		res.Code = code
+	}
+
+	return res, true
+}